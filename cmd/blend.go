@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var blendMode string
+var compositeOp string
+
+var blendCmd = &cobra.Command{
+	Use:   "blend <top> <bottom>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Blend a color onto another with blend modes",
+	Long: `Blend the top (source) color onto the bottom (backdrop) color.
+
+Blend modes (--mode), as in CSS mix-blend-mode:
+  ` + strings.Join(color.BlendModeNames, ", ") + `
+
+Compositing operators (--op), Porter-Duff:
+  ` + strings.Join(color.CompositeNames, ", ") + `
+
+The top, bottom and the resulting colors are previewed on a checkerboard, then
+the result is printed in the format given by --format (the format of the top
+color by default).
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		top, err := color.ParseColor(args[0], !nofallback)
+		if err != nil {
+			log.Fatalf("%s: %v", args[0], err)
+		}
+		bottom, err := color.ParseColor(args[1], !nofallback)
+		if err != nil {
+			log.Fatalf("%s: %v", args[1], err)
+		}
+
+		mode, ok := color.ParseBlendMode(blendMode)
+		if !ok {
+			log.Fatalf("Unknown blend mode: %s", blendMode)
+		}
+		op, ok := color.ParseComposite(compositeOp)
+		if !ok {
+			log.Fatalf("Unknown compositing operator: %s", compositeOp)
+		}
+
+		result := top.Composite(bottom, mode, op)
+
+		if structuredOutput() {
+			printDocuments([]any{display.NewColorDocument(result)})
+			return
+		}
+
+		outFormat := format
+		if outFormat == "" {
+			outFormat = color.DetectFormat(args[0])
+		}
+		repr, ok := result.FormatAs(outFormat)
+		if !ok {
+			log.Fatalf("Unknown format: %s", outFormat)
+		}
+
+		if isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain {
+			images := make([]string, 3)
+			for i, c := range []color.RepaColor{top, bottom, result} {
+				images[i] = display.RenderAnsiImage(display.GetColorAnsiImage(c, display.ColorAnsiImageOptions{}))
+			}
+			preview := display.MergeStringsVertically(display.MergeStringsVertically(images[0], images[1], 24), images[2], 24)
+			fmt.Printf("%s\n%-24s %-24s %s\n\n", preview, "top", "bottom", color.BlendModeNames[mode]+" / "+color.CompositeNames[op])
+		}
+		fmt.Println(repr)
+	},
+}
+
+func init() {
+	blendCmd.Flags().StringVarP(&blendMode, "mode", "m", "normal", "Blend mode (normal, multiply, screen, overlay, ...)")
+	blendCmd.Flags().StringVar(&compositeOp, "op", "source-over", "Compositing operator (source-over, source-in, xor, ...)")
+	blendCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print the plain value only")
+	blendCmd.Flags().StringVarP(&format, "format", "f", "", "Output format (hex, rgb, hsl, lab, lch, oklab, oklch, ...), defaults to the format of the top color")
+	blendCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(blendCmd)
+}
@@ -18,9 +18,18 @@ var compareCmd = &cobra.Command{
 	Short: "Compare the given colors",
 	Long: `Compare the given colors in the terminal.
 
+Every color is compared with the first one. The colors can also be read from a
+palette file with --palette.
+
 For supported input formats, see the 'display' command.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 2 {
+		if gamutMap {
+			color.GamutMethod = color.GAMUT_CSS4
+		}
+		var paletteColors []color.RepaColor
+		if paletteSource != "" {
+			args, paletteColors = readPaletteInputs(args)
+		} else if len(args) < 2 {
 			// read from stdin
 			inputReader := cmd.InOrStdin()
 			scanner := bufio.NewScanner(inputReader)
@@ -33,19 +42,41 @@ For supported input formats, see the 'display' command.`,
 			log.Fatal("At least two colors are required")
 		}
 
-		refcolor, err := color.ParseColor(args[0], !nofallback)
+		cvdKinds, cvdMethod := getCvdOptions()
+
+		parse := func(i int) (color.RepaColor, error) {
+			if paletteColors != nil {
+				return paletteColors[i], nil
+			}
+			return color.ParseColor(args[i], !nofallback)
+		}
+
+		refcolor, err := parse(0)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		// compare colors with the first one
-		for _, arg := range args[1:] {
-			c, err := color.ParseColor(arg, !nofallback)
+		var docs []any
+		for i, arg := range args {
+			if i == 0 {
+				continue
+			}
+			c, err := parse(i)
 			if err != nil {
 				log.Println(err)
 				continue
 			}
 
+			if structuredOutput() {
+				doc := display.NewCompareDocument(refcolor, c)
+				doc.Reference.Input, doc.Color.Input = args[0], arg
+				doc.Reference.CVD = display.NewCvdDocuments(refcolor, cvdKinds, cvdSeverity, cvdMethod)
+				doc.Color.CVD = display.NewCvdDocuments(c, cvdKinds, cvdSeverity, cvdMethod)
+				docs = append(docs, doc)
+				continue
+			}
+
 			ansirepr := display.RenderAnsiImage(display.GetCompareAnsiImage(refcolor, c, display.ColorAnsiImageOptions{}))
 			textrepr1 := "\n" + display.TextColorDetails(refcolor)
 			textrepr2 := "\n" + display.TextColorDetails(c)
@@ -63,6 +94,22 @@ For supported input formats, see the 'display' command.`,
 				refcolor.DistanceCIEDE2000(c.Color),
 			)
 
+			// contrast
+			fmt.Printf("  Contrast:\n    WCAG 2: %.2f:1 APCA: Lc %.1f (2nd on 1st) Lc %.1f (1st on 2nd)\n\n",
+				refcolor.ContrastRatio(c),
+				c.APCAContrast(refcolor),
+				refcolor.APCAContrast(c),
+			)
+
+			// color vision deficiencies
+			if len(cvdKinds) > 0 {
+				fmt.Printf("%s\n%s\n\n  CIEDE as seen:\n    %s\n\n",
+					cvdSwatches(refcolor, cvdKinds, cvdMethod),
+					cvdSwatches(c, cvdKinds, cvdMethod),
+					cvdDistances(refcolor, c, cvdKinds, cvdMethod),
+				)
+			}
+
 			// gradients
 			terminalWidth, _, _ := term.GetSize(0)
 			if terminalWidth <= 4 {
@@ -83,9 +130,17 @@ For supported input formats, see the 'display' command.`,
 				fmt.Printf("  %s\n", grad)
 			}
 		}
+
+		if structuredOutput() {
+			printDocuments(docs)
+		}
 	},
 }
 
 func init() {
+	compareCmd.Flags().BoolVar(&gamutMap, "gamut-map", false, "Map out of gamut colors with the CSS Color 4 algorithm instead of clipping")
+	addCvdFlags(compareCmd)
+	addPaletteFlag(compareCmd)
+
 	rootCmd.AddCommand(compareCmd)
 }
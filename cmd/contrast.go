@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var matrix bool
+var requireLevel string
+var fix bool
+var fixTarget float64
+
+var contrastCmd = &cobra.Command{
+	Use:   "contrast <fg> <bg>...",
+	Short: "Check the contrast of colors against WCAG 2",
+	Long: `Check the contrast of a foreground color against one or more backgrounds.
+
+For every pair the WCAG 2 contrast ratio is printed with its verdicts for
+AA/AAA normal and large text, and for UI components.
+
+In matrix mode (--matrix) every color of the palette (arguments, stdin lines or
+a palette file given by --palette) is checked against every other one, and the
+ratios are shown as a table.
+
+The command exits with a non-zero code if any checked pair fails the level
+given by --require (AA, AA-large, AAA, AAA-large, UI or none).
+
+With --fix the closest foreground color (keeping its hue) that reaches the
+required level (or --target) is suggested for failing pairs, in the format of
+the input. With --apca the target is an APCA Lc value instead of a ratio.
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var paletteColors []color.RepaColor
+		if paletteSource != "" {
+			args, paletteColors = readPaletteInputs(args)
+		} else if len(args) < 2 {
+			// read from stdin
+			inputReader := cmd.InOrStdin()
+			scanner := bufio.NewScanner(inputReader)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line != "" {
+					args = append(args, line)
+				}
+			}
+		}
+		if len(args) < 2 {
+			log.Fatal("At least two colors are required")
+		}
+
+		var required *color.ContrastLevel
+		if !strings.EqualFold(requireLevel, "none") {
+			level, ok := color.GetWCAGLevel(requireLevel)
+			if !ok {
+				log.Fatalf("Unknown contrast level: %s", requireLevel)
+			}
+			required = &level
+		}
+
+		colors := paletteColors
+		if colors == nil {
+			for _, arg := range args {
+				c, err := color.ParseColor(arg, !nofallback)
+				if err != nil {
+					log.Fatalf("%s: %v", arg, err)
+				}
+				colors = append(colors, c)
+			}
+		}
+
+		useAnsi := isatty.IsTerminal(os.Stdout.Fd()) && !noansi
+
+		target := fixTarget
+		if target <= 0 {
+			level := color.WCAGLevels[0]
+			if required != nil {
+				level = *required
+			}
+			target = level.Ratio
+			if color.ContrastMethod == color.CONTRAST_APCA {
+				target = level.Lc
+			}
+		}
+
+		var failed bool
+		if structuredOutput() {
+			failed = printContrastDocuments(args, colors, required, target)
+		} else if matrix {
+			if fix {
+				log.Println("--fix is not supported in matrix mode")
+			}
+			failed = printContrastMatrix(colors, required, useAnsi)
+		} else {
+			failed = printContrastPairs(args[0], colors[0], colors[1:], required, target, useAnsi)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// Foreground color as it is seen on the background
+func flattenOn(fg, bg color.RepaColor) color.RepaColor {
+	if fg.A >= 1 {
+		return fg
+	}
+	return fg.AlphaBlendRgb(bg, 1.0)
+}
+
+func verdict(pass, useAnsi bool) string {
+	switch {
+	case pass && useAnsi:
+		return "\033[32m✓ pass" + color.ANSI_RESET
+	case pass:
+		return "✓ pass"
+	case useAnsi:
+		return "\033[31m✗ fail" + color.ANSI_RESET
+	}
+	return "✗ fail"
+}
+
+func printContrastPairs(input string, fg color.RepaColor, bgs []color.RepaColor, required *color.ContrastLevel, target float64, useAnsi bool) bool {
+	failed := false
+
+	for _, bg := range bgs {
+		fgs := flattenOn(fg, bg)
+		ratio := fgs.ContrastRatio(bg)
+
+		sample := fmt.Sprintf("%s on %s", fg.Hex(), bg.Hex())
+		if useAnsi {
+			sample = bg.AnsiBg() + fgs.AnsiFg() + " " + sample + " " + color.ANSI_RESET
+		}
+		fmt.Printf("%s  %.2f:1 (APCA Lc %.1f)\n", sample, ratio, fgs.APCAContrast(bg))
+
+		for _, level := range color.WCAGLevels {
+			pass := ratio >= level.Ratio
+			mark := " "
+			if required != nil && level.Name == required.Name {
+				mark = "*"
+				if !pass {
+					failed = true
+				}
+			}
+			fmt.Printf(" %s %-16s %-5s %s\n", mark, level.Description, fmt.Sprintf("%g:1", level.Ratio), verdict(pass, useAnsi))
+		}
+
+		if fix && fgs.Contrast(bg, color.ContrastMethod) < target {
+			printContrastFix(input, fg, bg, target, useAnsi)
+		}
+		fmt.Println()
+	}
+
+	return failed
+}
+
+func printContrastFix(input string, fg, bg color.RepaColor, target float64, useAnsi bool) {
+	unit := fmt.Sprintf("%g:1", target)
+	if color.ContrastMethod == color.CONTRAST_APCA {
+		unit = fmt.Sprintf("Lc %g", target)
+	}
+
+	fixed, ok := fg.FixContrast(bg, target, color.ContrastMethod)
+	if !ok {
+		if fg.A < 1 {
+			fmt.Printf("   No color with the hue and alpha of %s reaches %s on %s\n", fg.Hex(), unit, bg.Hex())
+		} else {
+			fmt.Printf("   No color with the hue of %s reaches %s on %s\n", fg.Hex(), unit, bg.Hex())
+		}
+		return
+	}
+
+	repr, _ := fixed.FormatAs(color.DetectFormat(input))
+	fixeds := flattenOn(fixed, bg)
+	sample := repr
+	if useAnsi {
+		sample = bg.AnsiBg() + fixeds.AnsiFg() + " " + repr + " " + color.ANSI_RESET
+	}
+	fmt.Printf("   Suggested (%s): %s  %.2f:1 (APCA Lc %.1f)\n", unit, sample, fixeds.ContrastRatio(bg), fixeds.APCAContrast(bg))
+}
+
+func contrastDocument(input string, fg, bg color.RepaColor, required *color.ContrastLevel, target float64) display.ContrastPairDocument {
+	fgs := flattenOn(fg, bg)
+	ratio := fgs.ContrastRatio(bg)
+
+	doc := display.ContrastPairDocument{
+		Foreground: fg.Hex(),
+		Background: bg.Hex(),
+		Ratio:      math.Round(ratio*100) / 100,
+		APCA:       math.Round(fgs.APCAContrast(bg)*10) / 10,
+		Pass:       true,
+	}
+	for _, level := range color.WCAGLevels {
+		doc.Levels = append(doc.Levels, display.LevelDocument{Level: level.Name, Ratio: level.Ratio, Pass: ratio >= level.Ratio})
+	}
+	if required != nil {
+		doc.Required = required.Name
+		doc.Pass = ratio >= required.Ratio
+	}
+
+	if fix && fgs.Contrast(bg, color.ContrastMethod) < target {
+		if fixed, ok := fg.FixContrast(bg, target, color.ContrastMethod); ok {
+			doc.Suggestion, _ = fixed.FormatAs(color.DetectFormat(input))
+		}
+	}
+
+	return doc
+}
+
+// Contrast of the pairs (or every pair in matrix mode) as structured output
+func printContrastDocuments(inputs []string, colors []color.RepaColor, required *color.ContrastLevel, target float64) bool {
+	failed := false
+	var docs []any
+
+	for i, fg := range colors {
+		if !matrix && i > 0 {
+			break
+		}
+		for j, bg := range colors {
+			if i == j || (!matrix && j == 0) {
+				continue
+			}
+			doc := contrastDocument(inputs[i], fg, bg, required, target)
+			failed = failed || !doc.Pass
+			docs = append(docs, doc)
+		}
+	}
+
+	printDocuments(docs)
+	return failed
+}
+
+func printContrastMatrix(colors []color.RepaColor, required *color.ContrastLevel, useAnsi bool) bool {
+	const cellWidth = 10
+	failed := false
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-*s", cellWidth, "fg \\ bg"))
+	for _, bg := range colors {
+		sb.WriteString(fmt.Sprintf("%-*s", cellWidth, bg.Hex()))
+	}
+	sb.WriteString("\n")
+
+	for i, fg := range colors {
+		sb.WriteString(fmt.Sprintf("%-*s", cellWidth, fg.Hex()))
+		for j, bg := range colors {
+			fgs := flattenOn(fg, bg)
+			ratio := fgs.ContrastRatio(bg)
+
+			mark := " "
+			if i != j && required != nil && ratio < required.Ratio {
+				mark = "✗"
+				failed = true
+			}
+
+			cell := fmt.Sprintf(" %5.2f %s  ", ratio, mark)
+			if i == j {
+				cell = fmt.Sprintf("%-*s", cellWidth, "   -")
+			}
+			if useAnsi {
+				cell = bg.AnsiBg() + fgs.AnsiFg() + cell + color.ANSI_RESET
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Print(sb.String())
+
+	if required != nil {
+		fmt.Printf("\nRequired: %s (%g:1)\n", required.Description, required.Ratio)
+	}
+
+	return failed
+}
+
+func init() {
+	contrastCmd.Flags().BoolVarP(&matrix, "matrix", "m", false, "Check every color of the palette against every other one")
+	contrastCmd.Flags().StringVarP(&requireLevel, "require", "r", "AA", "Required level (AA, AA-large, AAA, AAA-large, UI, none)")
+	contrastCmd.Flags().BoolVar(&fix, "fix", false, "Suggest the closest passing foreground color for failing pairs")
+	contrastCmd.Flags().Float64Var(&fixTarget, "target", 0, "Contrast ratio (or APCA Lc with --apca) to reach with --fix, defaults to the required level")
+	contrastCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+	addPaletteFlag(contrastCmd)
+
+	rootCmd.AddCommand(contrastCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var cvd string
+var cvdSeverity float64
+var cvdMethod string
+
+func addCvdFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cvd, "cvd", "", "Simulate color vision deficiencies (protan, deutan, tritan, achroma, comma separated or 'all')")
+	cmd.Flags().Float64Var(&cvdSeverity, "cvd-severity", 1, "Severity of the simulated deficiencies (0-1)")
+	cmd.Flags().StringVar(&cvdMethod, "cvd-method", "machado", "Simulation method (machado, vienot, brettel)")
+}
+
+// Deficiencies and method selected with the --cvd flags
+func getCvdOptions() ([]int, int) {
+	if cvd == "" {
+		return nil, 0
+	}
+
+	method, ok := color.ParseCVDMethod(cvdMethod)
+	if !ok {
+		log.Fatalf("Unknown CVD simulation method: %s", cvdMethod)
+	}
+
+	if strings.EqualFold(cvd, "all") {
+		return []int{color.CVD_PROTAN, color.CVD_DEUTAN, color.CVD_TRITAN, color.CVD_ACHROMA}, method
+	}
+
+	var kinds []int
+	for _, name := range strings.Split(cvd, ",") {
+		kind, ok := color.ParseCVD(name)
+		if !ok {
+			log.Fatalf("Unknown color vision deficiency: %s", name)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	return kinds, method
+}
+
+// Swatches of the color as seen with each deficiency
+func cvdSwatches(c color.RepaColor, kinds []int, method int) string {
+	return display.CvdSwatches(c, kinds, cvdSeverity, method)
+}
+
+// Distances of the colors as seen with each deficiency
+func cvdDistances(c1, c2 color.RepaColor, kinds []int, method int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("normal: %f", c1.DistanceCIEDE2000(c2.Color)))
+	for _, kind := range kinds {
+		s1 := c1.SimulateCVD(kind, cvdSeverity, method)
+		s2 := c2.SimulateCVD(kind, cvdSeverity, method)
+		sb.WriteString(fmt.Sprintf(" %s: %f", color.CVDNames[kind], s1.DistanceCIEDE2000(s2.Color)))
+	}
+	return sb.String()
+}
@@ -16,6 +16,8 @@ import (
 
 var format string
 var noansi bool
+var gamutMap bool
+var cssVars []string
 
 // displayCmd represents the display command
 var displayCmd = &cobra.Command{
@@ -27,6 +29,19 @@ Supported input formats:
 - Hex: #RRGGBB
 - RGB: rgb(R G B [/ A])
 - HSL: hsl(H S% L% [/ A])
+- LAB/LCH: lab(L A B [/ A]), lch(L C H [/ A])
+- OKLAB/OKLCH: oklab(L A B [/ A]), oklch(L C H [/ A])
+- Color: color(<space> C1 C2 C3 [/ A])
+  (srgb, srgb-linear, display-p3, rec2020, a98-rgb, prophoto-rgb, xyz)
+- Mix: color-mix([in <space> [<hue method> hue],] <color> [P%], <color> [P%])
+- Relative colors: oklch(from <color> calc(l + 0.1) c h), rgb(from <color> b g r), ...
+  with channel keywords and calc() (+ - * /)
+- Variables: var(--name[, fallback]), defined with --var --name=value
+- Names: CSS, X11, xkcd and Crayola names, also misspelled or with light, dark,
+  pale or deep modifiers ("dark sea gren", "pale teal"), not with --nofallback
+
+The colors can also be read from a palette file with --palette (see the
+'palette' command for the supported formats).
 
 Supported output formats:
 - Hex
@@ -36,21 +51,46 @@ Supported output formats:
 - LCH
 - OKLAB
 - OKLCH
+- XYZ
+- Display P3, Rec. 2020, A98 RGB, ProPhoto RGB, linear sRGB (srgb-linear)
+
+Colors keep the coordinates of their input color space, so converting to the
+same format returns the exact input, even if it's outside of sRGB.
+Colors outside of the sRGB gamut are clipped, use --gamut-map to map them
+using the CSS Color 4 gamut mapping algorithm instead.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			// read from stdin
-			inputReader := cmd.InOrStdin()
-			scanner := bufio.NewScanner(inputReader)
-			for scanner.Scan() {
-				line := scanner.Text()
-				args = append(args, line)
-			}
+		if gamutMap {
+			color.GamutMethod = color.GAMUT_CSS4
 		}
-		for _, arg := range args {
-			c, err := color.ParseColor(arg, !nofallback)
-			if err != nil {
-				log.Println(err)
+		var paletteColors []color.RepaColor
+		if paletteSource != "" {
+			args, paletteColors = readPaletteInputs(args)
+		} else {
+			args = readInputs(cmd, args)
+		}
+		cvdKinds, cvdMethod := getCvdOptions()
+		vars := getCssVars()
+
+		var docs []any
+		for i, arg := range args {
+			var info color.ColorInfo
+			if paletteColors != nil {
+				info.Color = paletteColors[i]
+			} else {
+				var err error
+				info, err = color.ParseColorInfo(arg, vars, !nofallback)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+			}
+			c := info.Color
+
+			if structuredOutput() {
+				doc := display.NewColorInfoDocument(arg, info)
+				doc.CVD = display.NewCvdDocuments(c, cvdKinds, cvdSeverity, cvdMethod)
+				docs = append(docs, doc)
 				continue
 			}
 
@@ -58,27 +98,16 @@ Supported output formats:
 			var termrepr string
 
 			switch strings.ToLower(format) {
-			case "hex":
-				repr = c.Hex()
-			case "rgb", "rgba":
-				repr = c.RgbString()
-			case "hsl", "hsla":
-				repr = c.HslString()
-			case "lab":
-				repr = c.LabString()
-			case "lch":
-				repr = c.LchString()
-			case "oklab":
-				repr = c.OkLabString()
-			case "oklch":
-				repr = c.OkLchString()
-			case "xyz":
-				repr = c.XyzString()
 			case "text":
 				termrepr = display.TextColorDetails(c)
 			case "ansi":
 				termrepr = display.RenderAnsiImage(display.GetColorAnsiImage(c, display.ColorAnsiImageOptions{}))
 			default:
+				if s, ok := c.FormatAs(format); ok {
+					repr = s
+					break
+				}
+
 				ansirepr := display.RenderAnsiImage(display.GetColorAnsiImage(c, display.ColorAnsiImageOptions{}))
 				textrepr := "\n" + display.TextColorDetails(c)
 
@@ -87,23 +116,77 @@ Supported output formats:
 
 			// Print the color
 			if isatty.IsTerminal(os.Stdout.Fd()) && !noansi {
+				if desc := info.Description(); desc != "" {
+					fmt.Printf("%s: %s\n", arg, desc)
+				} else if paletteColors != nil {
+					fmt.Printf("%s\n", arg)
+				}
 				if termrepr == "" {
 					termrepr = fmt.Sprintf("%s%s\033[0m\n", c.AnsiBg(), repr)
 				}
 				fmt.Print(termrepr)
+				if len(cvdKinds) > 0 {
+					fmt.Printf("\n%s\n", cvdSwatches(c, cvdKinds, cvdMethod))
+				}
 			} else {
+				if desc := info.Description(); desc != "" {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", arg, desc)
+				}
 				if repr == "" {
 					repr = c.Hex()
 				}
 				fmt.Printf("%s\n", repr)
+				for _, kind := range cvdKinds {
+					sim := c.SimulateCVD(kind, cvdSeverity, cvdMethod)
+					simrepr, ok := sim.FormatAs(format)
+					if !ok {
+						simrepr = sim.Hex()
+					}
+					fmt.Printf("  %s: %s\n", color.CVDNames[kind], simrepr)
+				}
 			}
 		}
+
+		if structuredOutput() {
+			printDocuments(docs)
+		}
 	},
 }
 
+// Colors from the arguments, or from the lines of stdin if there are none
+func readInputs(cmd *cobra.Command, args []string) []string {
+	if len(args) == 0 {
+		// read from stdin
+		inputReader := cmd.InOrStdin()
+		scanner := bufio.NewScanner(inputReader)
+		for scanner.Scan() {
+			line := scanner.Text()
+			args = append(args, line)
+		}
+	}
+	return args
+}
+
+// Variables defined with --var name=value
+func getCssVars() map[string]string {
+	vars := map[string]string{}
+	for _, v := range cssVars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			log.Fatalf("Invalid variable definition: %s", v)
+		}
+		vars[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return vars
+}
+
 func init() {
-	displayCmd.Flags().StringVarP(&format, "format", "f", "", "Output format (hex, rgb, hsl, lab, lch, oklab, oklch)")
+	displayCmd.Flags().StringVarP(&format, "format", "f", "", "Output format (hex, rgb, hsl, lab, lch, oklab, oklch, xyz, display-p3, rec2020, a98-rgb, prophoto-rgb, srgb-linear)")
 	displayCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+	displayCmd.Flags().StringArrayVar(&cssVars, "var", nil, "Define a variable for var() references as name=value (repeatable)")
+	displayCmd.Flags().BoolVar(&gamutMap, "gamut-map", false, "Map out of gamut colors with the CSS Color 4 algorithm instead of clipping")
+	addCvdFlags(displayCmd)
+	addPaletteFlag(displayCmd)
 
 	rootCmd.AddCommand(displayCmd)
 }
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var extractCount int
+var extractMethod string
+var extractWeight string
+var extractAlphaThreshold float64
+
+var extractCmd = &cobra.Command{
+	Use:   "extract <image>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Extract the dominant colors of an image",
+	Long: `Extract a palette of the dominant colors of a PNG, JPEG or GIF image (use - to
+read the image from stdin).
+
+The colors are clustered in OKLab with k-means (the default, finds the
+distinct colors of the image) or median cut (--method median-cut, splits the
+colors into groups of similar weight, good for gradients and photos). Large
+images are sampled.
+
+Weighting (--weight):
+  count   every pixel counts the same
+  chroma  saturated colors count more, so the accents of logos and
+          screenshots aren't lost among the neutral backgrounds
+  center  the center of the image counts more than the edges
+
+Pixels with an alpha below --alpha-threshold are ignored, the others are
+weighted by their alpha.
+
+The colors are shown as swatches with their share of the image, or printed as
+plain values in the format given by --format (hex by default) with --plain or
+when the output is not a terminal.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		method, ok := color.ParseExtractMethod(extractMethod)
+		if !ok {
+			log.Fatalf("Unknown extraction method: %s", extractMethod)
+		}
+		weighting, ok := color.ParseExtractWeight(extractWeight)
+		if !ok {
+			log.Fatalf("Unknown weighting: %s", extractWeight)
+		}
+
+		img := readImage(cmd, args[0])
+		swatches := color.ExtractPalette(img, color.ExtractOptions{
+			Count:          extractCount,
+			Method:         method,
+			Weighting:      weighting,
+			AlphaThreshold: extractAlphaThreshold,
+		})
+
+		if structuredOutput() {
+			docs := []any{}
+			for _, s := range swatches {
+				docs = append(docs, display.ExtractDocument{Color: display.NewColorDocument(s.Color), Weight: s.Weight})
+			}
+			printDocuments(docs)
+			return
+		}
+
+		if len(swatches) == 0 {
+			log.Fatal("The image has no opaque pixels")
+		}
+
+		if isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain {
+			fmt.Println(display.RenderExtractedSwatches(swatches, 10))
+			return
+		}
+
+		outFormat := format
+		if outFormat == "" {
+			outFormat = "hex"
+		}
+		for _, s := range swatches {
+			repr, ok := s.Color.FormatAs(outFormat)
+			if !ok {
+				log.Fatalf("Unknown format: %s", outFormat)
+			}
+			fmt.Println(repr)
+		}
+	},
+}
+
+// Decode the image file (or stdin for -)
+func readImage(cmd *cobra.Command, path string) image.Image {
+	var r io.Reader
+	if path == "-" {
+		r = cmd.InOrStdin()
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	return img
+}
+
+func init() {
+	extractCmd.Flags().IntVarP(&extractCount, "count", "n", 6, "Number of colors")
+	extractCmd.Flags().StringVarP(&extractMethod, "method", "m", "kmeans", "Clustering method (kmeans, median-cut)")
+	extractCmd.Flags().StringVarP(&extractWeight, "weight", "w", "count", "Pixel weighting (count, chroma, center)")
+	extractCmd.Flags().Float64Var(&extractAlphaThreshold, "alpha-threshold", .5, "Ignore pixels with lower alpha (0-1)")
+	extractCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print plain values instead of swatches")
+	extractCmd.Flags().StringVarP(&format, "format", "f", "", "Output format of plain values (hex, rgb, hsl, lab, lch, oklab, oklch, ...)")
+	extractCmd.Flags().BoolVar(&noansi, "no-ansi", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(extractCmd)
+}
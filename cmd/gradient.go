@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var gradientSpace string
+var gradientHue string
+var gradientEasing string
+var gradientSamples int
+
+var gradientCmd = &cobra.Command{
+	Use:   "gradient <stop> <stop>...",
+	Args:  cobra.MinimumNArgs(2),
+	Short: "Render multi-stop gradients",
+	Long: `Render a gradient through the given color stops.
+
+Stops are colors with an optional position, like in CSS: "red", "#00f 30%",
+"oklch(70% 0.1 200) 80%". Stops without a position are spread evenly between
+their neighbours.
+
+The gradient is interpolated in the color space given by --in (oklab by
+default), polar spaces (hsl, lch, oklch) use the hue interpolation method given
+by --hue (shorter, longer, increasing or decreasing). --easing changes the
+transition between every pair of stops.
+
+With --samples N evenly spaced colors are printed from the gradient (e.g. as a
+data visualization palette), in the format given by --format (hex by default).
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		g := color.Gradient{}
+		for _, arg := range args {
+			stop, err := color.ParseGradientStop(arg, !nofallback)
+			if err != nil {
+				log.Fatalf("%s: %v", arg, err)
+			}
+			g.Stops = append(g.Stops, stop)
+		}
+
+		var ok bool
+		g.Space, ok = color.ParseColorSpace(gradientSpace)
+		if !ok {
+			log.Fatalf("Unknown color space: %s", gradientSpace)
+		}
+		g.Hue, ok = color.ParseHueMethod(gradientHue)
+		if !ok {
+			log.Fatalf("Unknown hue interpolation method: %s", gradientHue)
+		}
+		g.Easing, ok = color.ParseEasing(gradientEasing)
+		if !ok {
+			log.Fatalf("Unknown easing: %s", gradientEasing)
+		}
+
+		if structuredOutput() {
+			doc := display.GradientDocument{
+				Space:  gradientSpace,
+				Hue:    color.HueMethodNames[g.Hue],
+				Easing: color.EasingNames[g.Easing],
+			}
+			for _, stop := range g.ResolvedStops() {
+				doc.Stops = append(doc.Stops, display.GradientStopDocument{Position: stop.Position, Color: display.NewColorDocument(stop.Color)})
+			}
+			for _, c := range g.Sample(gradientSamples) {
+				doc.Samples = append(doc.Samples, display.NewColorDocument(c))
+			}
+			printDocuments([]any{doc})
+			return
+		}
+
+		useAnsi := isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain
+
+		if useAnsi {
+			terminalWidth, _, _ := term.GetSize(0)
+			if terminalWidth <= 4 {
+				terminalWidth = 80
+			}
+			grad := display.AnsiMultiGradient(g, terminalWidth-4)
+			fmt.Printf("  %s\n  %s\n", grad, grad)
+		}
+
+		if gradientSamples <= 0 {
+			return
+		}
+		if useAnsi {
+			fmt.Println()
+		}
+
+		outFormat := format
+		if outFormat == "" {
+			outFormat = "hex"
+		}
+		for _, c := range g.Sample(gradientSamples) {
+			repr, ok := c.FormatAs(outFormat)
+			if !ok {
+				log.Fatalf("Unknown format: %s", outFormat)
+			}
+			fmt.Println(repr)
+		}
+	},
+}
+
+func init() {
+	gradientCmd.Flags().StringVar(&gradientSpace, "in", "oklab", "Interpolation color space (srgb, srgb-linear, hsl, lab, lch, oklab, oklch, xyz, display-p3, ...)")
+	gradientCmd.Flags().StringVar(&gradientHue, "hue", "shorter", "Hue interpolation method for polar spaces (shorter, longer, increasing, decreasing)")
+	gradientCmd.Flags().StringVar(&gradientEasing, "easing", "linear", "Easing between the stops (linear, ease-in, ease-out, ease-in-out, smoothstep)")
+	gradientCmd.Flags().IntVarP(&gradientSamples, "samples", "c", 0, "Print N evenly spaced colors of the gradient")
+	gradientCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print plain values only")
+	gradientCmd.Flags().StringVarP(&format, "format", "f", "", "Output format of the sampled values (hex, rgb, hsl, lab, lch, oklab, oklch, ...)")
+	gradientCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(gradientCmd)
+}
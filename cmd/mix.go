@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var mixSpace string
+var mixHue string
+
+var mixCmd = &cobra.Command{
+	Use:   "mix <color1> [pct] <color2> [pct]",
+	Args:  cobra.RangeArgs(2, 4),
+	Short: "Mix two colors like CSS color-mix()",
+	Long: `Mix two colors following the CSS color-mix() rules.
+
+Each color can be followed by its percentage. If only one percentage is given,
+the other color gets the rest, without percentages the colors are mixed 50-50.
+If the percentages add up to more than 100% they are scaled down, if they add
+up to less, the result becomes partially transparent.
+
+The colors are interpolated in the color space given by --in (oklab by
+default) with premultiplied alpha, polar spaces (hsl, lch, oklch) use the hue
+interpolation method given by --hue.
+
+color-mix() expressions are also accepted by every command, e.g.:
+  repacolor display "color-mix(in oklch longer hue, red 30%, blue)"
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var colors []color.RepaColor
+		var inputs []string
+		pcts := []float64{-1, -1}
+
+		for _, arg := range args {
+			if strings.HasSuffix(arg, "%") && len(colors) > 0 {
+				if p, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64); err == nil {
+					if p < 0 || p > 100 {
+						log.Fatalf("Percentage out of range: %s", arg)
+					}
+					pcts[len(colors)-1] = p / 100
+					continue
+				}
+			}
+			if len(colors) == 2 {
+				log.Fatalf("Unexpected argument: %s", arg)
+			}
+
+			c, err := color.ParseColor(arg, !nofallback)
+			if err != nil {
+				log.Fatalf("%s: %v", arg, err)
+			}
+			colors = append(colors, c)
+			inputs = append(inputs, arg)
+		}
+		if len(colors) != 2 {
+			log.Fatal("Two colors are required")
+		}
+
+		mode, ok := color.ParseColorSpace(mixSpace)
+		if !ok {
+			log.Fatalf("Unknown color space: %s", mixSpace)
+		}
+		hueMethod, ok := color.ParseHueMethod(mixHue)
+		if !ok {
+			log.Fatalf("Unknown hue interpolation method: %s", mixHue)
+		}
+
+		mixed, err := color.Mix(colors[0], colors[1], pcts[0], pcts[1], mode, hueMethod)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if structuredOutput() {
+			printDocuments([]any{display.NewColorDocument(mixed)})
+			return
+		}
+
+		outFormat := format
+		if outFormat == "" {
+			outFormat = color.DetectFormat(inputs[0])
+		}
+		repr, ok := mixed.FormatAs(outFormat)
+		if !ok {
+			log.Fatalf("Unknown format: %s", outFormat)
+		}
+
+		if isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain {
+			swatches := []color.RepaColor{colors[0], mixed, colors[1]}
+			labels := []string{colors[0].Hex(), "mix", colors[1].Hex()}
+			fmt.Printf("%s\n\n", display.RenderSwatchRow(swatches, labels, 10))
+		}
+		fmt.Println(repr)
+	},
+}
+
+func init() {
+	mixCmd.Flags().StringVar(&mixSpace, "in", "oklab", "Interpolation color space (srgb, srgb-linear, hsl, lab, lch, oklab, oklch, xyz, display-p3, ...)")
+	mixCmd.Flags().StringVar(&mixHue, "hue", "shorter", "Hue interpolation method for polar spaces (shorter, longer, increasing, decreasing)")
+	mixCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print the plain value only")
+	mixCmd.Flags().StringVarP(&format, "format", "f", "", "Output format (hex, rgb, hsl, lab, lch, oklab, oklch, ...), defaults to the format of the first color")
+	mixCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(mixCmd)
+}
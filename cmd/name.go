@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var nameDicts string
+var nameTop int
+var nameDistance string
+
+var nameCmd = &cobra.Command{
+	Use:   "name <color>...",
+	Short: "Find the closest named colors",
+	Long: `Find the closest named colors to the given colors.
+
+Dictionaries (--dict, comma separated or 'all'):
+  ` + strings.Join(color.NameDictionaries, ", ") + `
+
+The distance is CIEDE2000 (0-100, below ~2 is hardly noticeable) or deltaEOK
+(--distance deltaeok, below ~0.02 is hardly noticeable).
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			// read from stdin
+			inputReader := cmd.InOrStdin()
+			scanner := bufio.NewScanner(inputReader)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line != "" {
+					args = append(args, line)
+				}
+			}
+		}
+
+		dicts := color.NameDictionaries
+		if !strings.EqualFold(nameDicts, "all") {
+			dicts = strings.Split(nameDicts, ",")
+		}
+		distance, ok := color.ParseDistance(nameDistance)
+		if !ok {
+			log.Fatalf("Unknown distance: %s", nameDistance)
+		}
+
+		useAnsi := isatty.IsTerminal(os.Stdout.Fd()) && !noansi
+
+		var docs []any
+		for i, arg := range args {
+			c, err := color.ParseColor(arg, !nofallback)
+			if err != nil {
+				log.Fatalf("%s: %v", arg, err)
+			}
+
+			matches, err := color.NearestNames(c, dicts, nameTop, distance)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if structuredOutput() {
+				doc := display.NamesDocument{Input: arg, Hex: c.Hex()}
+				for _, m := range matches {
+					doc.Matches = append(doc.Matches, display.NewNameDocument(m))
+				}
+				docs = append(docs, doc)
+				continue
+			}
+
+			if len(args) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				header := c.Hex()
+				if useAnsi {
+					header = c.AnsiBg() + "   " + color.ANSI_RESET + " " + header
+				}
+				fmt.Println(header)
+			}
+
+			for _, m := range matches {
+				swatch := ""
+				if useAnsi {
+					swatch = m.Color.AnsiBg() + "   " + color.ANSI_RESET + " "
+				}
+				fmt.Printf("%s%-24s %s  %6.2f  (%s)\n", swatch, m.Name, m.Color.Hex(), m.Distance, m.Dictionary)
+			}
+		}
+
+		if structuredOutput() {
+			printDocuments(docs)
+		}
+	},
+}
+
+func init() {
+	nameCmd.Flags().StringVarP(&nameDicts, "dict", "d", "css", "Dictionaries to search, comma separated or 'all'")
+	nameCmd.Flags().IntVarP(&nameTop, "top", "t", 1, "Number of names to show")
+	nameCmd.Flags().StringVar(&nameDistance, "distance", "ciede2000", "Color distance (ciede2000, deltaeok)")
+	nameCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(nameCmd)
+}
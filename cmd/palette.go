@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+	"github.com/dyuri/repacolor/palette"
+)
+
+var paletteFormat string
+var paletteName string
+var paletteFile string
+var paletteSource string
+
+var paletteCmd = &cobra.Command{
+	Use:   "palette",
+	Short: "Export color palettes",
+	Long: `Export color palettes to the file formats of design and paint applications.
+
+Formats:
+  gpl        GIMP palette (also Inkscape, Krita)
+  ase        Adobe Swatch Exchange
+  aco        Photoshop color swatches (version 1 and 2)
+  paintnet   Paint.NET palette (.txt, at most 96 colors)
+  dtcg       W3C design tokens (DTCG), see the 'tokens' command
+  style-dictionary
+             Style Dictionary tokens
+
+Palette files can also be used as the source of colors by the display, compare
+and contrast commands (--palette). Besides the formats above, Procreate
+swatches (.swatches), Sketch palettes (.sketchpalette) and the color variables
+of CSS, SCSS and Less files are read.`,
+}
+
+var paletteExportCmd = &cobra.Command{
+	Use:   "export [color]...",
+	Short: "Export colors as a palette",
+	Long: `Export the given colors (or the lines of stdin) as a palette.
+
+With --palette the colors of a palette file are exported, so palettes and
+design tokens can be converted between the formats. It cannot be combined
+with color arguments.
+
+The palette is written to stdout, or to the file given by --file. The format
+is given by --format, or guessed from the extension of the file (gpl by
+default). Binary formats (ase, aco) are not written to terminals.
+
+Named colors keep their names, other colors are named by their hex value.
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if paletteSource == "" {
+			args = readInputs(cmd, args)
+		} else if len(args) > 0 {
+			log.Fatal("--palette cannot be combined with color arguments")
+		}
+		vars := getCssVars()
+
+		pformat := palette.FORMAT_GPL
+		if paletteFormat != "" {
+			f, ok := palette.ParseFormat(paletteFormat)
+			if !ok {
+				log.Fatalf("Unknown palette format: %s", paletteFormat)
+			}
+			pformat = f
+		} else if paletteFile != "" {
+			if f, ok := palette.DetectFormat(paletteFile); ok {
+				pformat = f
+			}
+		}
+
+		var p palette.Palette
+		if paletteSource != "" {
+			// convert a palette file, keeping the names and groups
+			p = readPaletteFile()
+		}
+		for _, arg := range args {
+			if strings.TrimSpace(arg) == "" {
+				continue
+			}
+			info, err := color.ParseColorInfo(arg, vars, !nofallback)
+			if err != nil {
+				log.Fatalf("%s: %v", arg, err)
+			}
+			p.Add(entryName(info), info.Color)
+		}
+		if paletteName != "" {
+			p.Name = paletteName
+		}
+
+		var buf bytes.Buffer
+		if err := palette.Write(&buf, p, pformat); err != nil {
+			log.Fatal(err)
+		}
+
+		if paletteFile != "" {
+			if err := os.WriteFile(paletteFile, buf.Bytes(), 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if structuredOutput() {
+			printDocuments([]any{display.NewPaletteDocument(p)})
+			return
+		}
+
+		if paletteFile == "" {
+			if palette.FormatBinary[pformat] && isatty.IsTerminal(os.Stdout.Fd()) {
+				log.Fatalf("Not writing a binary %s palette to the terminal, use --file or redirect the output", palette.FormatNames[pformat])
+			}
+			os.Stdout.Write(buf.Bytes())
+		}
+	},
+}
+
+// Palette entry name of a parsed color: the color name if it was given by
+// name, or its CSS name if it has one
+func entryName(info color.ColorInfo) string {
+	if info.Source == color.SOURCE_NAME {
+		return strings.Join(append(info.Modifiers, info.Name), " ")
+	}
+	if info.Source == color.SOURCE_CSS {
+		if name, ok := color.GetName(info.Color); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func addPaletteFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&paletteSource, "palette", "", "Read the colors from a palette file (gpl, ase, aco, txt, swatches, sketchpalette, css, scss, tokens json) instead of the arguments")
+}
+
+// Palette file given by --palette, the errors are logged and the colors that
+// could be read are used
+func readPaletteFile() palette.Palette {
+	p, err := palette.ReadFile(paletteSource)
+	if err != nil {
+		logPaletteErrors(paletteSource, err)
+	}
+	if len(p.Colors) == 0 {
+		log.Fatalf("%s: no colors in the palette", paletteSource)
+	}
+	return p
+}
+
+// Log the errors of the palette file one by one, palettes can have more
+// invalid entries
+func logPaletteErrors(name string, err error) {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			log.Printf("%s: %v", name, e)
+		}
+		return
+	}
+	log.Print(err)
+}
+
+// Colors of the palette file given by --palette, the entry names are used
+// as the inputs. The colors come from the palette only, color arguments are
+// rejected.
+func readPaletteInputs(args []string) ([]string, []color.RepaColor) {
+	if len(args) > 0 {
+		log.Fatal("--palette cannot be combined with color arguments")
+	}
+	p := readPaletteFile()
+
+	inputs := make([]string, len(p.Colors))
+	colors := make([]color.RepaColor, len(p.Colors))
+	for i, e := range p.Colors {
+		inputs[i] = e.Label()
+		if e.Group != "" {
+			inputs[i] = e.Group + " / " + inputs[i]
+		}
+		colors[i] = e.Color
+	}
+	return inputs, colors
+}
+
+func init() {
+	paletteExportCmd.Flags().StringVarP(&paletteFormat, "format", "f", "", "Palette format (gpl, ase, aco, paintnet, dtcg, style-dictionary)")
+	paletteExportCmd.Flags().StringVar(&paletteName, "name", "", "Palette name")
+	paletteExportCmd.Flags().StringVar(&paletteFile, "file", "", "Write the palette to this file instead of stdout")
+	paletteExportCmd.Flags().StringArrayVar(&cssVars, "var", nil, "Define a variable for var() references as name=value (repeatable)")
+	addPaletteFlag(paletteExportCmd)
+
+	paletteCmd.AddCommand(paletteExportCmd)
+	rootCmd.AddCommand(paletteCmd)
+}
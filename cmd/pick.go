@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
 	"github.com/dyuri/repacolor/picker"
 )
 
@@ -20,12 +23,20 @@ var pickCmd = &cobra.Command{
 			c, _ = color.ParseColor(args[0], true)
 		}
 
-		picker.RunPicker(c, showAlpha)
+		cvdKinds, cvdMethod := getCvdOptions()
+		picked := picker.RunPicker(c, showAlpha, picker.CvdOptions{Kinds: cvdKinds, Method: cvdMethod, Severity: cvdSeverity})
+
+		if structuredOutput() {
+			printDocuments([]any{display.NewColorDocument(picked)})
+			return
+		}
+		fmt.Println(picked.Hex())
 	},
 }
 
 func init() {
 	pickCmd.Flags().BoolVarP(&showAlpha, "alpha", "a", false, "Show alpha channel")
+	addCvdFlags(pickCmd)
 
 	rootCmd.AddCommand(pickCmd)
 }
@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"log"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
 )
 
 var nofallback bool
+var apca bool
+var output string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -16,6 +23,26 @@ var rootCmd = &cobra.Command{
 
 It can be used to display and convert colors between different formats, and generate color palettes.
 It is meant to be used as a utility for developers and designers who work with colors.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if apca {
+			color.ContrastMethod = color.CONTRAST_APCA
+		}
+		output = strings.ToLower(output)
+		if output != "" && !display.IsOutputFormat(output) {
+			log.Fatalf("Unknown output: %s", output)
+		}
+	},
+}
+
+// Structured output is requested with --output
+func structuredOutput() bool {
+	return output != "" && output != "text"
+}
+
+func printDocuments(docs []any) {
+	if err := display.WriteDocuments(os.Stdout, output, docs); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -33,6 +60,8 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().BoolVar(&nofallback, "nofallback", false, "Don't fall back to deterministic random colors if input cannot be parsed")
+	rootCmd.PersistentFlags().BoolVar(&apca, "apca", false, "Use APCA instead of WCAG 2 contrast to choose text colors")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output type (text, json, yaml, ndjson), structured outputs are lists of records")
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.repacolor.yaml)")
 
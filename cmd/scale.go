@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var scaleOptions color.ScaleOptions
+var scaleSpace string
+var scaleCurve string
+var scaleLabels string
+var cssName string
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale <color>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Generate a tints and shades scale",
+	Long: `Generate a light to dark scale (Tailwind style 50-950 ramp) from the given color.
+
+The lightness of the steps follows the given curve between --lightest and
+--darkest in OKLCH or LCH, the hue of the color is kept and the chroma is
+eased towards the ends of the scale.
+
+The scale is shown as a ramp, or printed as plain values (--plain) or CSS
+custom properties (--css) in the format given by --format (hex by default).
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := color.ParseColor(args[0], !nofallback)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		options := scaleOptions
+		mode, ok := color.ParseColorSpace(scaleSpace)
+		if !ok || (mode != color.CS_LCH && mode != color.CS_OKLCH) {
+			log.Fatalf("Unsupported scale space: %s", scaleSpace)
+		}
+		options.Space = mode
+
+		options.Easing, ok = color.ParseEasing(scaleCurve)
+		if !ok {
+			log.Fatalf("Unknown curve: %s", scaleCurve)
+		}
+
+		if scaleLabels != "" {
+			options.Labels = strings.Split(scaleLabels, ",")
+			if !cmd.Flags().Changed("steps") {
+				options.Steps = len(options.Labels)
+			}
+		}
+
+		steps := color.Scale(c, options)
+
+		if structuredOutput() {
+			var docs []any
+			for _, step := range steps {
+				docs = append(docs, display.ScaleStepDocument{Label: step.Label, Color: display.NewColorDocument(step.Color)})
+			}
+			printDocuments(docs)
+			return
+		}
+
+		outFormat := format
+		if outFormat == "" {
+			outFormat = "hex"
+		}
+		values := make([]string, len(steps))
+		for i, step := range steps {
+			values[i], ok = step.Color.FormatAs(outFormat)
+			if !ok {
+				log.Fatalf("Unknown format: %s", outFormat)
+			}
+		}
+
+		if cssName != "" {
+			fmt.Println(":root {")
+			for i, step := range steps {
+				fmt.Printf("  --%s-%s: %s;\n", cssName, step.Label, values[i])
+			}
+			fmt.Println("}")
+			return
+		}
+
+		if isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain {
+			colors := make([]color.RepaColor, len(steps))
+			labels := make([]string, len(steps))
+			for i, step := range steps {
+				colors[i] = step.Color
+				labels[i] = step.Label
+			}
+			fmt.Printf("%s\n\n", display.RenderSwatchRow(colors, labels, 7))
+		}
+
+		for i, step := range steps {
+			fmt.Printf("%s %s\n", step.Label, values[i])
+		}
+	},
+}
+
+func init() {
+	scaleCmd.Flags().IntVar(&scaleOptions.Steps, "steps", 11, "Number of steps")
+	scaleCmd.Flags().StringVar(&scaleLabels, "labels", "", "Comma separated step labels (default 50,100,...,900,950 for 11 steps)")
+	scaleCmd.Flags().StringVarP(&scaleSpace, "space", "s", "oklch", "Color space of the scale (oklch, lch)")
+	scaleCmd.Flags().StringVar(&scaleCurve, "curve", "linear", "Lightness curve (linear, ease-in, ease-out, ease-in-out, smoothstep)")
+	scaleCmd.Flags().Float64Var(&scaleOptions.Lightest, "lightest", .97, "Lightness of the lightest step (0-1)")
+	scaleCmd.Flags().Float64Var(&scaleOptions.Darkest, "darkest", .27, "Lightness of the darkest step (0-1)")
+	scaleCmd.Flags().Float64Var(&scaleOptions.ChromaFalloff, "chroma-falloff", .5, "Chroma reduction towards the ends of the scale (0-1)")
+	scaleCmd.Flags().BoolVar(&scaleOptions.KeepBase, "keep-base", false, "Use the input color for the closest step")
+	scaleCmd.Flags().StringVar(&cssName, "css", "", "Print the scale as CSS custom properties with the given name")
+	scaleCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print plain values instead of the ramp")
+	scaleCmd.Flags().StringVarP(&format, "format", "f", "", "Output format of the values (hex, rgb, hsl, lab, lch, oklab, oklch, ...)")
+	scaleCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(scaleCmd)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var schemeType string
+var hueSpace string
+var plain bool
+
+var schemeCmd = &cobra.Command{
+	Use:   "scheme <color>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Generate color schemes",
+	Long: `Generate color schemes from the given color.
+
+Schemes: complementary, split-complementary, analogous, triadic, tetradic and
+monochromatic. The hues are rotated (or the lightness is changed for the
+monochromatic scheme) in the selected hue space (hsl, lch or oklch).
+
+The colors are shown as swatches, or printed as plain values in the format
+given by --format (hex by default) with --plain or when the output is not a
+terminal.
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := color.ParseColor(args[0], !nofallback)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mode, ok := color.ParseColorSpace(hueSpace)
+		if !ok || (mode != color.CS_HSL && mode != color.CS_LCH && mode != color.CS_OKLCH) {
+			log.Fatalf("Unsupported hue space: %s", hueSpace)
+		}
+
+		schemes := []int{}
+		if strings.EqualFold(schemeType, "all") {
+			for scheme := range color.SchemeNames {
+				schemes = append(schemes, scheme)
+			}
+		} else {
+			for _, name := range strings.Split(schemeType, ",") {
+				scheme, ok := color.ParseScheme(name)
+				if !ok {
+					log.Fatalf("Unknown scheme: %s", name)
+				}
+				schemes = append(schemes, scheme)
+			}
+		}
+
+		useAnsi := isatty.IsTerminal(os.Stdout.Fd()) && !noansi && !plain
+		outFormat := format
+		if outFormat == "" {
+			outFormat = "hex"
+		}
+
+		if structuredOutput() {
+			var docs []any
+			for _, scheme := range schemes {
+				doc := display.SchemeDocument{Scheme: color.SchemeNames[scheme]}
+				for _, sc := range color.Scheme(c, scheme, mode) {
+					doc.Colors = append(doc.Colors, display.NewColorDocument(sc))
+				}
+				docs = append(docs, doc)
+			}
+			printDocuments(docs)
+			return
+		}
+
+		for i, scheme := range schemes {
+			colors := color.Scheme(c, scheme, mode)
+
+			if useAnsi {
+				labels := make([]string, len(colors))
+				for j, sc := range colors {
+					labels[j] = sc.Hex()
+				}
+				fmt.Printf("%s\n%s\n\n", color.SchemeNames[scheme], display.RenderSwatchRow(colors, labels, 10))
+				continue
+			}
+
+			if len(schemes) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("# %s\n", color.SchemeNames[scheme])
+			}
+			for _, sc := range colors {
+				repr, ok := sc.FormatAs(outFormat)
+				if !ok {
+					log.Fatalf("Unknown format: %s", outFormat)
+				}
+				fmt.Println(repr)
+			}
+		}
+	},
+}
+
+func init() {
+	schemeCmd.Flags().StringVarP(&schemeType, "type", "t", "all", "Scheme type(s), comma separated or 'all'")
+	schemeCmd.Flags().StringVarP(&hueSpace, "space", "s", "oklch", "Hue space (hsl, lch, oklch)")
+	schemeCmd.Flags().BoolVarP(&plain, "plain", "p", false, "Print plain values instead of swatches")
+	schemeCmd.Flags().StringVarP(&format, "format", "f", "", "Output format of plain values (hex, rgb, hsl, lab, lch, oklab, oklch, ...)")
+	schemeCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(schemeCmd)
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+	"github.com/dyuri/repacolor/theme"
+)
+
+var themeBackground string
+var themeForeground string
+var themeAccents []string
+var themeMinContrast float64
+var themeFormat string
+var themeName string
+var themeFile string
+var themeSource string
+
+var themeCmd = &cobra.Command{
+	Use:   "theme [accent]...",
+	Short: "Generate terminal color themes",
+	Long: `Generate a terminal color theme (16 ANSI colors, cursor and selection colors)
+from a background, a foreground and accent colors.
+
+Every seed is optional: the background is a dark gray tinted with the first
+accent, the foreground is a light (or on light backgrounds a dark) gray. The
+accents (given as arguments or with --accent) replace the ANSI colors with the
+closest hue, the other ANSI colors get the same chroma and evenly spaced
+lightness. The first accent is also used for the cursor and the selection.
+
+Text colors are adjusted to reach the minimum contrast ratio (--min-contrast,
+WCAG 2) on the background, bright black (used for dimmed text) gets at least
+3:1.
+
+With --palette the seeds are read from a palette file: the entries named
+"background" (or "bg") and "foreground" (or "fg") are used as such, the others
+are accents.
+
+With --from an existing theme file is used instead (see 'theme show' for the
+supported formats), e.g. to convert a base16 scheme to editor color schemes.
+
+Formats (--format):
+  alacritty         Alacritty (TOML)
+  kitty             kitty (include it in kitty.conf)
+  foot              foot (include it in foot.ini)
+  wezterm           WezTerm color scheme (TOML)
+  windows-terminal  Windows Terminal color scheme (JSON)
+  iterm2            iTerm2 color preset (.itermcolors)
+  xresources        Xresources (xterm, urxvt, st)
+  neovim            Neovim colorscheme (Lua, colors/<name>.lua)
+  vim               Vim colorscheme (colors/<name>.vim), with 256 color
+                    fallbacks for terminals without true color
+  vscode            VS Code color theme (color-theme.json)
+  helix             Helix theme (TOML, themes/<name>.toml)
+
+The editor formats use the base16 colors of the theme: the colors of the
+base16 scheme it was read from, or shades of the background and foreground
+and the ANSI colors.
+
+Without --format the colors are shown with their contrast ratios.
+
+For supported input formats, see the 'display' command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if themeSource != "" && (len(args) > 0 || len(themeAccents) > 0 || themeBackground != "" || themeForeground != "" || paletteSource != "") {
+			log.Fatal("--from cannot be combined with seed colors")
+		}
+
+		opts := theme.Options{
+			Name:        themeName,
+			Background:  color.NOCOLOR,
+			Foreground:  color.NOCOLOR,
+			MinContrast: themeMinContrast,
+		}
+
+		if paletteSource != "" {
+			p := readPaletteFile()
+			for _, e := range p.Colors {
+				switch strings.ToLower(e.Name) {
+				case "background", "bg":
+					opts.Background = e.Color
+				case "foreground", "fg":
+					opts.Foreground = e.Color
+				default:
+					opts.Accents = append(opts.Accents, e.Color)
+				}
+			}
+			if opts.Name == "" {
+				opts.Name = p.Name
+			}
+		}
+
+		parse := func(s string) color.RepaColor {
+			c, err := color.ParseColor(s, !nofallback)
+			if err != nil {
+				log.Fatalf("%s: %v", s, err)
+			}
+			return c
+		}
+		if themeBackground != "" {
+			opts.Background = parse(themeBackground)
+		}
+		if themeForeground != "" {
+			opts.Foreground = parse(themeForeground)
+		}
+		for _, a := range append(themeAccents, args...) {
+			opts.Accents = append(opts.Accents, parse(a))
+		}
+
+		var t theme.Theme
+		if themeSource != "" {
+			var err error
+			if t, err = theme.ReadFile(themeSource); err != nil {
+				log.Fatal(err)
+			}
+			if themeName != "" {
+				t.Name = themeName
+			}
+		} else {
+			t = theme.Generate(opts)
+		}
+
+		if themeFormat == "" && themeFile != "" {
+			log.Fatal("The theme format is required with --file")
+		}
+
+		if themeFormat != "" {
+			tformat, ok := theme.ParseFormat(themeFormat)
+			if !ok {
+				log.Fatalf("Unknown theme format: %s", themeFormat)
+			}
+
+			var buf bytes.Buffer
+			if err := theme.Write(&buf, t, tformat); err != nil {
+				log.Fatal(err)
+			}
+			if themeFile != "" {
+				if err := os.WriteFile(themeFile, buf.Bytes(), 0644); err != nil {
+					log.Fatal(err)
+				}
+			} else if !structuredOutput() {
+				os.Stdout.Write(buf.Bytes())
+			}
+		}
+
+		if structuredOutput() {
+			printDocuments([]any{display.NewThemeDocument(t)})
+			return
+		}
+
+		if themeFormat == "" {
+			printTheme(t, isatty.IsTerminal(os.Stdout.Fd()) && !noansi)
+		}
+	},
+}
+
+var themeShowCmd = &cobra.Command{
+	Use:   "show <file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Preview and audit a terminal theme",
+	Long: `Show a terminal theme file: a sample terminal session (prompt, ls, git diff,
+test output) rendered with its colors, and the contrast of the foreground and
+every ANSI color on the background.
+
+The text colors have to reach the level given by --require (AA by default),
+bright black (often used for dimmed text) only 3:1. Black in dark
+themes, white and bright white in light themes are usually background colors,
+they are not checked. The command exits with a non-zero code if a color fails.
+
+Formats: alacritty (TOML or YAML), kitty, foot, wezterm, windows-terminal
+(a scheme or settings.json), iterm2 (.itermcolors), xresources and base16
+(base16 and base24 YAML). The format is detected from the file, use --format
+to force it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var t theme.Theme
+		var err error
+		if themeFormat != "" {
+			tformat, ok := theme.ParseFormat(themeFormat)
+			if !ok {
+				log.Fatalf("Unknown theme format: %s", themeFormat)
+			}
+			data, rerr := os.ReadFile(args[0])
+			if rerr != nil {
+				log.Fatal(rerr)
+			}
+			t, err = theme.ReadFileFormat(args[0], data, tformat)
+		} else {
+			t, err = theme.ReadFile(args[0])
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var required *color.ContrastLevel
+		if !strings.EqualFold(requireLevel, "none") {
+			level, ok := color.GetWCAGLevel(requireLevel)
+			if !ok {
+				log.Fatalf("Unknown contrast level: %s", requireLevel)
+			}
+			required = &level
+		}
+
+		var failed bool
+		if structuredOutput() {
+			printDocuments([]any{display.NewThemeDocument(t)})
+			for _, check := range themeChecks(t, required) {
+				failed = failed || check.failed()
+			}
+		} else {
+			useAnsi := isatty.IsTerminal(os.Stdout.Fd()) && !noansi
+			if useAnsi {
+				fmt.Printf("%s\n%s\n", t.Name, display.RenderThemeSample(t, 0))
+			}
+			failed = printThemeAudit(t, required, useAnsi)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// A text color of a theme on its background, with the contrast it has to
+// reach (0 if it's not checked)
+type themeCheck struct {
+	name     string
+	fg, bg   color.RepaColor
+	required float64
+}
+
+// The foreground, the ANSI colors, the cursor and the selected text on the
+// background
+func themeChecks(t theme.Theme, required *color.ContrastLevel) []themeCheck {
+	text := 0.0
+	if required != nil {
+		text = required.Ratio
+	}
+
+	checks := []themeCheck{{"foreground", t.Foreground, t.Background, text}}
+	for i, c := range t.ANSI {
+		check := themeCheck{theme.ANSIName(i), c, t.Background, text}
+		switch {
+		case t.IsBackgroundColor(i):
+			check.required = 0
+		case i == 8:
+			check.required = math.Min(text, 3)
+		}
+		checks = append(checks, check)
+	}
+	return append(checks,
+		themeCheck{"cursor", t.Cursor, t.Background, 0},
+		themeCheck{"selection", t.SelectionForeground, t.SelectionBackground, text},
+	)
+}
+
+func (c themeCheck) failed() bool {
+	return c.required > 0 && c.fg.ContrastRatio(c.bg) < c.required
+}
+
+// Contrast of the text colors, returns true if a color fails the requirement
+func printThemeAudit(t theme.Theme, required *color.ContrastLevel, useAnsi bool) bool {
+	failed := false
+
+	if required != nil {
+		fmt.Printf("Required: %s %g:1, bright black %g:1\n", required.Description, required.Ratio, math.Min(required.Ratio, 3))
+	}
+	for _, check := range themeChecks(t, required) {
+		ratio := check.fg.ContrastRatio(check.bg)
+		sample := fmt.Sprintf("%s on %s", check.fg.Hex(), check.bg.Hex())
+		if useAnsi {
+			sample = check.bg.AnsiBg() + check.fg.AnsiFg() + " " + sample + " " + color.ANSI_RESET
+		}
+		result := "-"
+		if check.required > 0 {
+			result = verdict(!check.failed(), useAnsi)
+			failed = failed || check.failed()
+		}
+		fmt.Printf("%-15s %s %6.2f:1  Lc %6.1f  %s\n", check.name, sample, ratio, check.fg.APCAContrast(check.bg), result)
+	}
+
+	return failed
+}
+
+// Colors of the theme with their contrast ratios on the background, as
+// swatches or as plain lines
+func printTheme(t theme.Theme, useAnsi bool) {
+	colors := t.Colors()
+
+	if !useAnsi {
+		for _, nc := range colors {
+			fmt.Printf("%-21s %s %5.2f\n", nc.Name, nc.Color.Hex(), nc.Color.ContrastRatio(t.Background))
+		}
+		return
+	}
+
+	const width = 10
+	row := func(title string, colors []theme.NamedColor) {
+		swatches := make([]color.RepaColor, len(colors))
+		labels := make([]string, len(colors))
+		ratios := make([]string, len(colors))
+		for i, nc := range colors {
+			swatches[i] = nc.Color
+			labels[i] = nc.Color.Hex()
+			ratios[i] = fmt.Sprintf("%-*s", width, fmt.Sprintf("%.2f:1", nc.Color.ContrastRatio(t.Background)))
+		}
+		fmt.Printf("%s\n%s\n%s\n\n", title, display.RenderSwatchRow(swatches, labels, width), strings.TrimRight(strings.Join(ratios, " "), " "))
+	}
+
+	row("background, foreground, cursor, selection", []theme.NamedColor{colors[0], colors[1], colors[2], colors[4], colors[5]})
+	row("normal ("+strings.Join(theme.ANSINames, ", ")+")", colors[6:14])
+	row("bright", colors[14:])
+}
+
+func init() {
+	themeCmd.Flags().StringVar(&themeBackground, "bg", "", "Background color")
+	themeCmd.Flags().StringVar(&themeForeground, "fg", "", "Foreground color")
+	themeCmd.Flags().StringArrayVar(&themeAccents, "accent", nil, "Accent color (repeatable)")
+	themeCmd.Flags().Float64Var(&themeMinContrast, "min-contrast", 4.5, "Minimum WCAG 2 contrast ratio of the text colors on the background")
+	themeCmd.Flags().StringVarP(&themeFormat, "format", "f", "", "Theme format (alacritty, kitty, foot, wezterm, windows-terminal, iterm2, xresources, neovim, vim, vscode, helix)")
+	themeCmd.Flags().StringVar(&themeName, "name", "", "Theme name")
+	themeCmd.Flags().StringVar(&themeFile, "file", "", "Write the theme to this file instead of stdout")
+	themeCmd.Flags().StringVar(&themeSource, "from", "", "Read the theme from a theme file instead of generating it")
+	themeCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+	addPaletteFlag(themeCmd)
+
+	themeShowCmd.Flags().StringVarP(&themeFormat, "format", "f", "", "Theme format, detected from the file by default")
+	themeShowCmd.Flags().StringVarP(&requireLevel, "require", "r", "AA", "Required level of the text colors (AA, AA-large, AAA, AAA-large, UI, none)")
+	themeShowCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	themeCmd.AddCommand(themeShowCmd)
+	rootCmd.AddCommand(themeCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+	"github.com/dyuri/repacolor/palette"
+)
+
+var styleDictionary bool
+
+var tokensCmd = &cobra.Command{
+	Use:   "tokens <file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Show the color tokens of a design tokens file",
+	Long: `Show the color tokens of a W3C design tokens (DTCG) or Style Dictionary file
+as a tree of swatches.
+
+Aliases ({group.token}) are resolved, and every color value is validated. The
+values can be CSS colors, or DTCG color objects:
+  {"colorSpace": "oklch", "components": [0.6, 0.15, 250], "alpha": 1}
+
+Invalid values and unknown or circular aliases are reported, and the command
+exits with a non-zero code.
+
+Style Dictionary files (with "value" instead of "$value") are detected, use
+--style-dictionary to force it.
+
+Tokens can be converted to other palette formats with the 'palette export'
+command, e.g. 'palette export --palette tokens.json --format ase'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pformat := palette.FORMAT_DTCG
+		if styleDictionary {
+			pformat = palette.FORMAT_STYLEDICT
+		} else if f, ok := palette.DetectContentFormat(data); ok && f == palette.FORMAT_STYLEDICT {
+			pformat = f
+		}
+		p, err := palette.Read(bytes.NewReader(data), pformat)
+
+		if structuredOutput() {
+			var docs []any
+			for _, e := range p.Colors {
+				docs = append(docs, display.TokenDocument{
+					Path:  tokenName(e),
+					Alias: e.Alias,
+					Color: display.NewColorDocument(e.Color),
+				})
+			}
+			printDocuments(docs)
+		} else {
+			printTokenTree(p, isatty.IsTerminal(os.Stdout.Fd()) && !noansi)
+		}
+
+		if err != nil {
+			logPaletteErrors(args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+func tokenName(e palette.Entry) string {
+	if e.Group == "" {
+		return e.Label()
+	}
+	return e.Group + "." + e.Label()
+}
+
+func printTokenTree(p palette.Palette, useAnsi bool) {
+	var prev []string
+	for _, e := range p.Colors {
+		var path []string
+		if e.Group != "" {
+			path = strings.Split(e.Group, ".")
+		}
+
+		// headers of the groups that are different from the previous token
+		common := 0
+		for common < len(path) && common < len(prev) && path[common] == prev[common] {
+			common++
+		}
+		for i := common; i < len(path); i++ {
+			fmt.Printf("%s%s\n", strings.Repeat("  ", i), path[i])
+		}
+		prev = path
+
+		swatch := ""
+		if useAnsi {
+			swatch = e.Color.AnsiBg() + "   " + color.ANSI_RESET + " "
+		}
+		value := e.Color.Hex()
+		if format != "" {
+			if repr, ok := e.Color.FormatAs(format); ok {
+				value = repr
+			}
+		}
+		alias := ""
+		if e.Alias != "" {
+			alias = "  → " + e.Alias
+		}
+		fmt.Printf("%s%s%-12s %s%s\n", strings.Repeat("  ", len(path)), swatch, e.Label(), value, alias)
+	}
+}
+
+func init() {
+	tokensCmd.Flags().BoolVar(&styleDictionary, "style-dictionary", false, "Read the file as Style Dictionary tokens")
+	tokensCmd.Flags().StringVarP(&format, "format", "f", "", "Output format of the colors (hex, rgb, hsl, lab, lch, oklab, oklch, ...)")
+	tokensCmd.Flags().BoolVarP(&noansi, "no-ansi", "n", false, "Disable ANSI color codes")
+
+	rootCmd.AddCommand(tokensCmd)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"image"
+	imgcolor "image/color"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/display"
+)
+
+var viewWidth int
+var viewHeight int
+var viewFilter string
+var viewCellRatio float64
+var viewBackground string
+var viewQuantize int
+
+// gap between the images, in columns
+const viewGap = 2
+
+var viewCmd = &cobra.Command{
+	Use:   "view <image>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Show an image in the terminal",
+	Long: `Show a PNG, JPEG or GIF image (use - to read it from stdin) in the terminal,
+with half-block characters and 24 bit colors, e.g. to check the colors of
+assets over ssh.
+
+The image is scaled to fit the terminal (or --width and --height, in
+columns and lines), keeping its aspect ratio. --cell-ratio is the height of
+the terminal cells relative to their width (2 in most fonts). Images are not
+enlarged. The resampling filter is given by --filter:
+  nearest      keeps hard pixel edges (pixel art, icons)
+  box          average of the covered pixels
+  bilinear     smooth, a bit blurry
+  catmull-rom  sharp and smooth (default)
+  lanczos      sharpest, may ring at hard edges
+
+Previews are shown next to the image:
+  --cvd        the image as seen with color vision deficiencies
+  --palette    the image reduced to the colors of a palette file
+  --quantize   the image reduced to its N dominant colors (see 'extract')
+
+Transparent pixels show the terminal background, semi-transparent pixels are
+drawn on --bg if it's given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, ok := display.ParseFilter(viewFilter)
+		if !ok {
+			log.Fatalf("Unknown resampling filter: %s", viewFilter)
+		}
+
+		bg := color.NOCOLOR
+		if viewBackground != "" {
+			var err error
+			if bg, err = color.ParseColor(viewBackground, !nofallback); err != nil {
+				log.Fatalf("%s: %v", viewBackground, err)
+			}
+		}
+
+		img := readImage(cmd, args[0])
+
+		kinds, method := getCvdOptions()
+		var palette []color.RepaColor
+		paletteLabel := ""
+		if paletteSource != "" {
+			for _, e := range readPaletteFile().Colors {
+				palette = append(palette, e.Color)
+			}
+			paletteLabel = fmt.Sprintf("palette (%d)", len(palette))
+		} else if viewQuantize > 0 {
+			for _, s := range color.ExtractPalette(img, color.ExtractOptions{Count: viewQuantize, AlphaThreshold: .5}) {
+				palette = append(palette, s.Color)
+			}
+			paletteLabel = fmt.Sprintf("quantized (%d)", len(palette))
+		}
+
+		panels := 1 + len(kinds)
+		if palette != nil {
+			panels++
+		}
+
+		columns, lines := viewWidth, viewHeight
+		if columns <= 0 || lines <= 0 {
+			tw, th, err := term.GetSize(int(os.Stdout.Fd()))
+			if err != nil || tw <= 0 || th <= 0 {
+				tw, th = 80, 24
+			}
+			if columns <= 0 {
+				columns = tw
+			}
+			if lines <= 0 {
+				// room for the labels and the prompt
+				lines = th - 2
+			}
+		}
+		maxWidth := (columns - viewGap*(panels-1)) / panels
+		if maxWidth < 1 || lines < 1 {
+			log.Fatal("The terminal is too small")
+		}
+
+		// a cell is two pixels high
+		bounds := img.Bounds()
+		width, height := display.FitSize(bounds.Dx(), bounds.Dy(), maxWidth, 2*lines, viewCellRatio/2)
+		if height%2 == 1 && height > 1 {
+			height--
+		}
+
+		resized := display.ResizeImage(img, width, height, filter)
+		if bg != color.NOCOLOR {
+			resized = display.MapImage(resized, func(c imgcolor.NRGBA) imgcolor.NRGBA {
+				return nrgba(nrgbaColor(c).AlphaBlendRgb(bg, 1))
+			})
+		}
+
+		images := []*image.NRGBA{resized}
+		labels := []string{"original"}
+		for _, kind := range kinds {
+			images = append(images, display.MapImage(resized, func(c imgcolor.NRGBA) imgcolor.NRGBA {
+				return nrgba(nrgbaColor(c).SimulateCVD(kind, cvdSeverity, method))
+			}))
+			labels = append(labels, color.CVDNames[kind])
+		}
+		if palette != nil {
+			images = append(images, quantizeImage(resized, palette))
+			labels = append(labels, paletteLabel)
+		}
+
+		if len(images) == 1 {
+			fmt.Println(display.RenderAnsiImage(resized))
+			return
+		}
+
+		// side by side, with transparent gaps
+		row := image.NewNRGBA(image.Rect(0, 0, len(images)*(width+viewGap)-viewGap, height))
+		var header strings.Builder
+		for i, panel := range images {
+			offset := i * (width + viewGap)
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					row.SetNRGBA(offset+x, y, panel.NRGBAAt(x, y))
+				}
+			}
+			label := labels[i]
+			if len(label) > width {
+				label = label[:width]
+			}
+			header.WriteString(fmt.Sprintf("%-*s", width+viewGap, label))
+		}
+		fmt.Printf("%s\n%s\n", strings.TrimRight(header.String(), " "), display.RenderAnsiImage(row))
+	},
+}
+
+func nrgbaColor(c imgcolor.NRGBA) color.RepaColor {
+	return color.CreateColor(color.CS_RGB, float64(c.R)/255, float64(c.G)/255, float64(c.B)/255, float64(c.A)/255)
+}
+
+func nrgba(c color.RepaColor) imgcolor.NRGBA {
+	r, g, b, a := c.RGBA256()
+	return imgcolor.NRGBA{r, g, b, a}
+}
+
+// Replace the pixels with the closest (by deltaEOK) palette color
+func quantizeImage(img *image.NRGBA, palette []color.RepaColor) *image.NRGBA {
+	cache := map[[3]uint8]imgcolor.NRGBA{}
+	return display.MapImage(img, func(c imgcolor.NRGBA) imgcolor.NRGBA {
+		key := [3]uint8{c.R, c.G, c.B}
+		q, ok := cache[key]
+		if !ok {
+			pc := nrgbaColor(c)
+			pc.A = 1
+			best := palette[0]
+			for _, p := range palette[1:] {
+				if color.DeltaEOK(pc, p) < color.DeltaEOK(pc, best) {
+					best = p
+				}
+			}
+			best.A = 1
+			q = nrgba(best)
+			cache[key] = q
+		}
+		q.A = c.A
+		return q
+	})
+}
+
+func init() {
+	viewCmd.Flags().IntVarP(&viewWidth, "width", "W", 0, "Maximum width in columns (terminal width by default)")
+	viewCmd.Flags().IntVarP(&viewHeight, "height", "H", 0, "Maximum height in lines (terminal height by default)")
+	viewCmd.Flags().StringVar(&viewFilter, "filter", "catmull-rom", "Resampling filter (nearest, box, bilinear, catmull-rom, lanczos)")
+	viewCmd.Flags().Float64Var(&viewCellRatio, "cell-ratio", 2, "Height of the terminal cells relative to their width")
+	viewCmd.Flags().StringVar(&viewBackground, "bg", "", "Draw semi-transparent pixels on this color")
+	viewCmd.Flags().IntVarP(&viewQuantize, "quantize", "q", 0, "Show the image reduced to its N dominant colors")
+	addCvdFlags(viewCmd)
+	addPaletteFlag(viewCmd)
+
+	rootCmd.AddCommand(viewCmd)
+}
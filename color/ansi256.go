@@ -0,0 +1,67 @@
+package color
+
+// The xterm 256 color palette: the 16 system colors (xterm defaults, most
+// terminals let the user change them), a 6x6x6 color cube (16-231) and a
+// gray ramp (232-255).
+
+var ansi256System = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var ansi256Levels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Color of the xterm 256 color palette index (0-255)
+func ANSI256Color(index int) RepaColor {
+	var r, g, b uint8
+	switch {
+	case index < 0 || index > 255:
+		return NOCOLOR
+	case index < 16:
+		r, g, b = ansi256System[index][0], ansi256System[index][1], ansi256System[index][2]
+	case index < 232:
+		i := index - 16
+		r, g, b = ansi256Levels[i/36], ansi256Levels[i/6%6], ansi256Levels[i%6]
+	default:
+		r = uint8(8 + 10*(index-232))
+		g, b = r, r
+	}
+	return CreateColor(CS_RGB, float64(r)/255, float64(g)/255, float64(b)/255, 1)
+}
+
+// Closest cube level to the 8 bit value
+func ansi256Level(v uint8) int {
+	best := 0
+	for i, level := range ansi256Levels {
+		if absDiff(v, level) < absDiff(v, ansi256Levels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// Index of the closest (by deltaEOK) color of the xterm 256 color palette.
+// Only the color cube and the gray ramp are used, the system colors depend
+// on the terminal theme.
+func (col RepaColor) ANSI256() int {
+	r, g, b := col.ToGamut().RGB256()
+	cube := 16 + 36*ansi256Level(r) + 6*ansi256Level(g) + ansi256Level(b)
+
+	// the gray ramp is 8, 18, ..., 238
+	avg := (int(r) + int(g) + int(b)) / 3
+	gray := 232 + min(max((avg-3)/10, 0), 23)
+
+	if DeltaEOK(col, ANSI256Color(gray)) < DeltaEOK(col, ANSI256Color(cube)) {
+		return gray
+	}
+	return cube
+}
@@ -0,0 +1,30 @@
+package color
+
+import "testing"
+
+func TestANSI256Color(t *testing.T) {
+	for index, expected := range map[int]string{1: "#cd0000", 16: "#000000", 21: "#0000ff", 196: "#ff0000", 231: "#ffffff", 232: "#080808", 255: "#eeeeee"} {
+		if hex := ANSI256Color(index).Hex(); hex != expected {
+			t.Fatalf("Wrong color %d: %s", index, hex)
+		}
+	}
+	if ANSI256Color(256) != NOCOLOR {
+		t.Fatalf("Invalid index should be NOCOLOR")
+	}
+}
+
+func TestANSI256(t *testing.T) {
+	for input, expected := range map[string]int{"#ff0000": 196, "#000000": 16, "#ffffff": 231, "#808080": 244, "#1d1f21": 234, "#5f87af": 67, "#ff8700": 208} {
+		c, _ := ParseColor(input, false)
+		if index := c.ANSI256(); index != expected {
+			t.Fatalf("Wrong index for %s: %d", input, index)
+		}
+	}
+
+	// palette colors are quantized to themselves
+	for index := 16; index < 256; index++ {
+		if q := ANSI256Color(index).ANSI256(); q != index {
+			t.Fatalf("Wrong index for %d: %d", index, q)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package color
+
+import (
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// W3C Compositing and Blending Level 1 blend modes
+const (
+	BLENDMODE_NORMAL     = iota
+	BLENDMODE_MULTIPLY   = iota
+	BLENDMODE_SCREEN     = iota
+	BLENDMODE_OVERLAY    = iota
+	BLENDMODE_DARKEN     = iota
+	BLENDMODE_LIGHTEN    = iota
+	BLENDMODE_COLORDODGE = iota
+	BLENDMODE_COLORBURN  = iota
+	BLENDMODE_HARDLIGHT  = iota
+	BLENDMODE_SOFTLIGHT  = iota
+	BLENDMODE_DIFFERENCE = iota
+	BLENDMODE_EXCLUSION  = iota
+	BLENDMODE_HUE        = iota
+	BLENDMODE_SATURATION = iota
+	BLENDMODE_COLOR      = iota
+	BLENDMODE_LUMINOSITY = iota
+)
+
+var BlendModeNames = []string{
+	"normal", "multiply", "screen", "overlay", "darken", "lighten",
+	"color-dodge", "color-burn", "hard-light", "soft-light", "difference",
+	"exclusion", "hue", "saturation", "color", "luminosity",
+}
+
+// Porter-Duff compositing operators
+const (
+	COMPOSITE_CLEAR            = iota
+	COMPOSITE_COPY             = iota
+	COMPOSITE_DESTINATION      = iota
+	COMPOSITE_SOURCE_OVER      = iota
+	COMPOSITE_DESTINATION_OVER = iota
+	COMPOSITE_SOURCE_IN        = iota
+	COMPOSITE_DESTINATION_IN   = iota
+	COMPOSITE_SOURCE_OUT       = iota
+	COMPOSITE_DESTINATION_OUT  = iota
+	COMPOSITE_SOURCE_ATOP      = iota
+	COMPOSITE_DESTINATION_ATOP = iota
+	COMPOSITE_XOR              = iota
+	COMPOSITE_LIGHTER          = iota
+)
+
+var CompositeNames = []string{
+	"clear", "copy", "destination", "source-over", "destination-over",
+	"source-in", "destination-in", "source-out", "destination-out",
+	"source-atop", "destination-atop", "xor", "lighter",
+}
+
+func ParseBlendMode(name string) (int, bool) {
+	return indexOfName(BlendModeNames, name)
+}
+
+func ParseComposite(name string) (int, bool) {
+	return indexOfName(CompositeNames, name)
+}
+
+func indexOfName(names []string, name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range names {
+		if n == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Separable blend function of a single channel, `cb` is the backdrop and `cs`
+// the source
+func blendChannel(mode int, cb, cs float64) float64 {
+	switch mode {
+	case BLENDMODE_MULTIPLY:
+		return cb * cs
+	case BLENDMODE_SCREEN:
+		return cb + cs - cb*cs
+	case BLENDMODE_OVERLAY:
+		return blendChannel(BLENDMODE_HARDLIGHT, cs, cb)
+	case BLENDMODE_DARKEN:
+		return math.Min(cb, cs)
+	case BLENDMODE_LIGHTEN:
+		return math.Max(cb, cs)
+	case BLENDMODE_COLORDODGE:
+		if cb == 0 {
+			return 0
+		}
+		if cs >= 1 {
+			return 1
+		}
+		return math.Min(1, cb/(1-cs))
+	case BLENDMODE_COLORBURN:
+		if cb >= 1 {
+			return 1
+		}
+		if cs == 0 {
+			return 0
+		}
+		return 1 - math.Min(1, (1-cb)/cs)
+	case BLENDMODE_HARDLIGHT:
+		if cs <= .5 {
+			return cb * 2 * cs
+		}
+		return blendChannel(BLENDMODE_SCREEN, cb, 2*cs-1)
+	case BLENDMODE_SOFTLIGHT:
+		if cs <= .5 {
+			return cb - (1-2*cs)*cb*(1-cb)
+		}
+		d := math.Sqrt(cb)
+		if cb <= .25 {
+			d = ((16*cb-12)*cb + 4) * cb
+		}
+		return cb + (2*cs-1)*(d-cb)
+	case BLENDMODE_DIFFERENCE:
+		return math.Abs(cb - cs)
+	case BLENDMODE_EXCLUSION:
+		return cb + cs - 2*cb*cs
+	}
+	return cs
+}
+
+// Helpers of the non-separable blend modes
+
+func lum(c [3]float64) float64 {
+	return .3*c[0] + .59*c[1] + .11*c[2]
+}
+
+func clipColor(c [3]float64) [3]float64 {
+	l := lum(c)
+	n := math.Min(c[0], math.Min(c[1], c[2]))
+	x := math.Max(c[0], math.Max(c[1], c[2]))
+	for i := range c {
+		if n < 0 {
+			c[i] = l + (c[i]-l)*l/(l-n)
+		}
+		if x > 1 {
+			c[i] = l + (c[i]-l)*(1-l)/(x-l)
+		}
+	}
+	return c
+}
+
+func setLum(c [3]float64, l float64) [3]float64 {
+	d := l - lum(c)
+	return clipColor([3]float64{c[0] + d, c[1] + d, c[2] + d})
+}
+
+func sat(c [3]float64) float64 {
+	return math.Max(c[0], math.Max(c[1], c[2])) - math.Min(c[0], math.Min(c[1], c[2]))
+}
+
+func setSat(c [3]float64, s float64) [3]float64 {
+	// indices of the min, mid and max components
+	mn, md, mx := 0, 1, 2
+	if c[mn] > c[md] {
+		mn, md = md, mn
+	}
+	if c[md] > c[mx] {
+		md, mx = mx, md
+	}
+	if c[mn] > c[md] {
+		mn, md = md, mn
+	}
+
+	var r [3]float64
+	if c[mx] > c[mn] {
+		r[md] = (c[md] - c[mn]) * s / (c[mx] - c[mn])
+		r[mx] = s
+	}
+	return r
+}
+
+// Blend the source color (col) onto the backdrop color with the blend mode
+// (BLENDMODE_*) and the Porter-Duff operator (COMPOSITE_*), as specified by
+// W3C Compositing and Blending Level 1. Colors are blended in sRGB.
+func (col RepaColor) Composite(backdrop RepaColor, mode int, operator int) RepaColor {
+	src := col.ToGamut()
+	dst := backdrop.ToGamut()
+	cs := [3]float64{src.R, src.G, src.B}
+	cb := [3]float64{dst.R, dst.G, dst.B}
+	as, ab := src.A, dst.A
+
+	var blended [3]float64
+	switch mode {
+	case BLENDMODE_HUE:
+		blended = setLum(setSat(cs, sat(cb)), lum(cb))
+	case BLENDMODE_SATURATION:
+		blended = setLum(setSat(cb, sat(cs)), lum(cb))
+	case BLENDMODE_COLOR:
+		blended = setLum(cs, lum(cb))
+	case BLENDMODE_LUMINOSITY:
+		blended = setLum(cb, lum(cs))
+	default:
+		for i := range blended {
+			blended[i] = blendChannel(mode, cb[i], cs[i])
+		}
+	}
+
+	// the blended color is only used where the backdrop is opaque
+	for i := range cs {
+		cs[i] = (1-ab)*cs[i] + ab*blended[i]
+	}
+
+	var fa, fb float64
+	switch operator {
+	case COMPOSITE_CLEAR:
+		fa, fb = 0, 0
+	case COMPOSITE_COPY:
+		fa, fb = 1, 0
+	case COMPOSITE_DESTINATION:
+		fa, fb = 0, 1
+	case COMPOSITE_DESTINATION_OVER:
+		fa, fb = 1-ab, 1
+	case COMPOSITE_SOURCE_IN:
+		fa, fb = ab, 0
+	case COMPOSITE_DESTINATION_IN:
+		fa, fb = 0, as
+	case COMPOSITE_SOURCE_OUT:
+		fa, fb = 1-ab, 0
+	case COMPOSITE_DESTINATION_OUT:
+		fa, fb = 0, 1-as
+	case COMPOSITE_SOURCE_ATOP:
+		fa, fb = ab, 1-as
+	case COMPOSITE_DESTINATION_ATOP:
+		fa, fb = 1-ab, as
+	case COMPOSITE_XOR:
+		fa, fb = 1-ab, 1-as
+	case COMPOSITE_LIGHTER:
+		fa, fb = 1, 1
+	default:
+		fa, fb = 1, 1-as
+	}
+
+	a := math.Min(1, as*fa+ab*fb)
+	if a == 0 {
+		return NOCOLOR
+	}
+
+	var co [3]float64
+	for i := range co {
+		co[i] = clamp01((as*fa*cs[i] + ab*fb*cb[i]) / a)
+	}
+
+	return RepaColor{Color: colorful.Color{R: co[0], G: co[1], B: co[2]}, A: a}
+}
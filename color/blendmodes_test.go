@@ -0,0 +1,86 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestCompositeBlendModes(t *testing.T) {
+	top, _ := ParseColor("#ff8040", false)
+	bottom, _ := ParseColor("#4080c0", false)
+
+	tests := []struct {
+		mode     int
+		expected string
+	}{
+		{BLENDMODE_NORMAL, "#ff8040"},
+		{BLENDMODE_MULTIPLY, "#404030"},
+		{BLENDMODE_SCREEN, "#ffc0d0"},
+		{BLENDMODE_DARKEN, "#408040"},
+		{BLENDMODE_LIGHTEN, "#ff80c0"},
+		{BLENDMODE_DIFFERENCE, "#bf0080"},
+		{BLENDMODE_EXCLUSION, "#bf7fa0"},
+		{BLENDMODE_OVERLAY, "#8080a1"},
+		{BLENDMODE_HARDLIGHT, "#ff8060"},
+	}
+
+	for _, test := range tests {
+		c := top.Composite(bottom, test.mode, COMPOSITE_SOURCE_OVER)
+		if c.Hex() != test.expected {
+			t.Fatalf("Wrong %s result: %s != %s", BlendModeNames[test.mode], c.Hex(), test.expected)
+		}
+	}
+}
+
+func TestCompositeNonSeparable(t *testing.T) {
+	top, _ := ParseColor("#ff8040", false)
+	bottom, _ := ParseColor("#4080c0", false)
+
+	c := top.Composite(bottom, BLENDMODE_LUMINOSITY, COMPOSITE_SOURCE_OVER)
+	if !almosteq(lum([3]float64{c.R, c.G, c.B}), lum([3]float64{top.R, top.G, top.B})) {
+		t.Fatalf("Luminosity should keep the luminance of the source")
+	}
+
+	c = top.Composite(bottom, BLENDMODE_COLOR, COMPOSITE_SOURCE_OVER)
+	if !almosteq(lum([3]float64{c.R, c.G, c.B}), lum([3]float64{bottom.R, bottom.G, bottom.B})) {
+		t.Fatalf("Color should keep the luminance of the backdrop")
+	}
+
+	c = GRAY.Composite(bottom, BLENDMODE_SATURATION, COMPOSITE_SOURCE_OVER)
+	if !almosteq(c.R, c.G) || !almosteq(c.G, c.B) {
+		t.Fatalf("Saturation of gray should result in gray: %v", c.Hex())
+	}
+
+	c = top.Composite(top, BLENDMODE_HUE, COMPOSITE_SOURCE_OVER)
+	if c.Hex() != top.Hex() {
+		t.Fatalf("Hue blend of the same color should not change it: %v", c.Hex())
+	}
+}
+
+func TestCompositeOperators(t *testing.T) {
+	src := RepaColor{Color: WHITE.Color, A: .5}
+	dst := RepaColor{Color: BLACK.Color, A: .5}
+
+	tests := []struct {
+		operator int
+		alpha    float64
+		r        float64
+	}{
+		{COMPOSITE_CLEAR, 0, 0},
+		{COMPOSITE_COPY, .5, 1},
+		{COMPOSITE_DESTINATION, .5, 0},
+		{COMPOSITE_SOURCE_OVER, .75, 2. / 3},
+		{COMPOSITE_DESTINATION_OVER, .75, 1. / 3},
+		{COMPOSITE_SOURCE_IN, .25, 1},
+		{COMPOSITE_DESTINATION_OUT, .25, 0},
+		{COMPOSITE_SOURCE_ATOP, .5, .5},
+		{COMPOSITE_XOR, .5, .5},
+		{COMPOSITE_LIGHTER, 1, .5},
+	}
+
+	for _, test := range tests {
+		c := src.Composite(dst, BLENDMODE_NORMAL, test.operator)
+		if !almosteq(c.A, test.alpha) || !almosteq(c.R, test.r) {
+			t.Fatalf("Wrong %s result: %v %v", CompositeNames[test.operator], c.R, c.A)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Evaluator of calc() expressions in relative colors: numbers, percentages,
+// angles, channel keywords, + - * / and parentheses
+
+type calcToken struct {
+	kind  rune // 'n'umber, 'i'dentifier or the operator itself
+	text  string
+	value float64
+}
+
+type calcParser struct {
+	tokens []calcToken
+	pos    int
+	// values of the channel keywords
+	vals map[string]float64
+	// 100% equals to
+	ref float64
+	hue bool
+}
+
+func tokenizeCalc(expr string) ([]calcToken, error) {
+	var tokens []calcToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/()", r):
+			tokens = append(tokens, calcToken{kind: r, text: string(r)})
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			// unit or percentage
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '%') {
+				j++
+			}
+			tokens = append(tokens, calcToken{kind: 'n', text: string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, calcToken{kind: 'i', text: strings.ToLower(string(runes[i:j]))})
+			i = j
+		default:
+			return nil, fmt.Errorf("calc(): unexpected character: %c", r)
+		}
+	}
+
+	return tokens, nil
+}
+
+// Evaluate a calc() expression (or its content), percentages are relative to
+// `ref`, units are only accepted for hues
+func evalCalc(expr string, vals map[string]float64, ref float64, hue bool) (float64, error) {
+	tokens, err := tokenizeCalc(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	p := &calcParser{tokens: tokens, vals: vals, ref: ref, hue: hue}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos != len(p.tokens) {
+		return 0, fmt.Errorf("calc(): unexpected token: %s", p.tokens[p.pos].text)
+	}
+
+	return v, nil
+}
+
+func (p *calcParser) peek() rune {
+	if p.pos >= len(p.tokens) {
+		return 0
+	}
+	return p.tokens[p.pos].kind
+}
+
+func (p *calcParser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.peek() == '+' || p.peek() == '-' {
+		op := p.tokens[p.pos].kind
+		p.pos++
+		w, err := p.term()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+
+	return v, nil
+}
+
+func (p *calcParser) term() (float64, error) {
+	v, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.peek() == '*' || p.peek() == '/' {
+		op := p.tokens[p.pos].kind
+		p.pos++
+		w, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v *= w
+		} else if w == 0 {
+			return 0, errors.New("calc(): division by zero")
+		} else {
+			v /= w
+		}
+	}
+
+	return v, nil
+}
+
+func (p *calcParser) factor() (float64, error) {
+	if p.pos >= len(p.tokens) {
+		return 0, errors.New("calc(): unexpected end of expression")
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case '-':
+		v, err := p.factor()
+		return -v, err
+	case '+':
+		return p.factor()
+	case '(':
+		return p.group()
+	case 'n':
+		return p.number(tok.text)
+	case 'i':
+		if p.peek() == '(' {
+			if tok.text != "calc" {
+				return 0, fmt.Errorf("calc(): unsupported function: %s()", tok.text)
+			}
+			p.pos++
+			return p.group()
+		}
+		if v, ok := p.vals[tok.text]; ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("calc(): unknown keyword: %s", tok.text)
+	}
+
+	return 0, fmt.Errorf("calc(): unexpected token: %s", tok.text)
+}
+
+// Parenthesized expression, the opening parenthesis is already consumed
+func (p *calcParser) group() (float64, error) {
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != ')' {
+		return 0, errors.New("calc(): missing closing parenthesis")
+	}
+	p.pos++
+	return v, nil
+}
+
+func (p *calcParser) number(s string) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		return parseNumber(s, p.ref)
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	if p.hue {
+		return parseHue(s)
+	}
+	return 0, fmt.Errorf("calc(): invalid number: %s", s)
+}
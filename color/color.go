@@ -11,6 +11,10 @@ import (
 type RepaColor struct {
 	colorful.Color
 	A float64
+	// color space and unclamped coordinates the color was created from
+	// (see CreateColor), not used for CS_RGB
+	Space  int
+	Coords [3]float64
 }
 
 const (
@@ -22,6 +26,13 @@ const (
 	CS_OKLAB = iota
 	CS_OKLCH = iota
 	CS_XYZ   = iota
+
+	CS_DISPLAYP3 = iota
+	CS_REC2020   = iota
+	CS_A98RGB    = iota
+	CS_PROPHOTO  = iota
+
+	CS_LINEARRGB = iota
 )
 
 const (
@@ -36,11 +47,11 @@ const (
 )
 
 var NOCOLOR = RepaColor{}
-var BLACK = RepaColor{colorful.Color{R: 0, G: 0, B: 0}, 1}
-var WHITE = RepaColor{colorful.Color{R: 1, G: 1, B: 1}, 1}
-var GRAY = RepaColor{colorful.Color{R: .5, G: .5, B: .5}, 1}
-var DARKGRAY = RepaColor{colorful.Color{R: .25, G: .25, B: .25}, 1}
-var LIGHTGRAY = RepaColor{colorful.Color{R: .75, G: .75, B: .75}, 1}
+var BLACK = RepaColor{Color: colorful.Color{R: 0, G: 0, B: 0}, A: 1}
+var WHITE = RepaColor{Color: colorful.Color{R: 1, G: 1, B: 1}, A: 1}
+var GRAY = RepaColor{Color: colorful.Color{R: .5, G: .5, B: .5}, A: 1}
+var DARKGRAY = RepaColor{Color: colorful.Color{R: .25, G: .25, B: .25}, A: 1}
+var LIGHTGRAY = RepaColor{Color: colorful.Color{R: .75, G: .75, B: .75}, A: 1}
 
 var ANSI_RESET = "\033[0m"
 
@@ -61,50 +72,107 @@ func formatFloat(f float64) string {
 	return fmt.Sprintf("%.4g", f)
 }
 
+// Create a color from the given color space coordinates.
+// Out of gamut colors are kept as is, they are mapped into sRGB when rendered.
 func CreateColor(mode int, v1, v2, v3, a float64) RepaColor {
+	var c colorful.Color
+
+	switch mode {
+	case CS_HSL:
+		c = colorful.Hsl(v1, v2, v3)
+	case CS_LAB:
+		c = colorful.Lab(v1, v2, v3)
+	case CS_LCH:
+		c = colorful.Hcl(v3, v2, v1)
+	case CS_HCL:
+		c = colorful.Hcl(v1, v2, v3)
+	case CS_OKLAB:
+		c = colorful.OkLab(v1, v2, v3)
+	case CS_OKLCH:
+		c = colorful.OkLch(v1, v2, v3)
+	case CS_XYZ:
+		c = colorful.Xyz(v1, v2, v3)
+	case CS_LINEARRGB:
+		c = colorful.LinearRgb(v1, v2, v3)
+	case CS_DISPLAYP3, CS_REC2020, CS_A98RGB, CS_PROPHOTO:
+		c = rgbSpaces[mode].toColor(v1, v2, v3)
+	default:
+		// use rgb as fallback
+		return RepaColor{Color: colorful.Color{R: v1, G: v2, B: v3}, A: a}
+	}
+
+	return RepaColor{Color: c, A: a, Space: mode, Coords: [3]float64{v1, v2, v3}}
+}
+
+// Coordinates of the color in the given color space, in the same order and
+// scale as CreateColor expects them. If the color was created in that space,
+// the original coordinates are returned.
+func (col RepaColor) Coordinates(mode int) (v1, v2, v3 float64) {
+	if mode != CS_RGB && col.Space == mode {
+		return col.Coords[0], col.Coords[1], col.Coords[2]
+	}
+
 	switch mode {
-	case CS_RGB:
-		return RepaColor{colorful.Color{R: v1, G: v2, B: v3}, a}
 	case CS_HSL:
-		return RepaColor{colorful.Hsl(v1, v2, v3), a}
+		return col.Hsl()
 	case CS_LAB:
-		return RepaColor{colorful.Lab(v1, v2, v3), a}
+		return col.Lab()
 	case CS_LCH:
-		return RepaColor{colorful.Hcl(v3, v2, v1), a}
+		if col.Space == CS_HCL {
+			return col.Coords[2], col.Coords[1], col.Coords[0]
+		}
+		return col.lch()
 	case CS_HCL:
-		return RepaColor{colorful.Hcl(v1, v2, v3), a}
+		if col.Space == CS_LCH {
+			return col.Coords[2], col.Coords[1], col.Coords[0]
+		}
+		l, c, h := col.lch()
+		return h, c, l
 	case CS_OKLAB:
-		return RepaColor{colorful.OkLab(v1, v2, v3), a}
+		return col.OkLab()
 	case CS_OKLCH:
-		return RepaColor{colorful.OkLch(v1, v2, v3), a}
+		return col.OkLch()
 	case CS_XYZ:
-		return RepaColor{colorful.Xyz(v1, v2, v3), a}
+		return col.Xyz()
+	case CS_LINEARRGB:
+		return col.LinearRgb()
+	case CS_DISPLAYP3, CS_REC2020, CS_A98RGB, CS_PROPHOTO:
+		return rgbSpaces[mode].fromColor(col.Color)
 	}
 
-	// use rgb as fallback
-	return RepaColor{colorful.Color{R: v1, G: v2, B: v3}, a}
+	return col.R, col.G, col.B
+}
+
+// colorful.Hcl() returns 0 hue if the a and b values are close to each other
+func (col RepaColor) lch() (l, c, h float64) {
+	l, a, b := col.Lab()
+	return l, math.Sqrt(a*a + b*b), normalizeHue(math.Atan2(b, a) * 180 / math.Pi)
 }
 
+// The integer representations are brought into the sRGB gamut first (see GamutMethod)
 func (col RepaColor) RGBA() (r, g, b, a uint32) {
-	r = uint32(col.A*col.R*0xffff + 0.5)
-	g = uint32(col.A*col.G*0xffff + 0.5)
-	b = uint32(col.A*col.B*0xffff + 0.5)
-	a = uint32(col.A*0xffff + 0.5)
+	c := col.ToGamut()
+	r = uint32(c.A*c.R*0xffff + 0.5)
+	g = uint32(c.A*c.G*0xffff + 0.5)
+	b = uint32(c.A*c.B*0xffff + 0.5)
+	a = uint32(c.A*0xffff + 0.5)
 	return
 }
 
 func (col RepaColor) RGB256() (r, g, b uint8) {
-	r = uint8(col.R*255.0 + 0.5)
-	g = uint8(col.G*255.0 + 0.5)
-	b = uint8(col.B*255.0 + 0.5)
+	c := col.ToGamut()
+	r = uint8(c.R*255.0 + 0.5)
+	g = uint8(c.G*255.0 + 0.5)
+	b = uint8(c.B*255.0 + 0.5)
 	return
 }
 
 func (col RepaColor) RGBA256() (r, g, b, a uint8) {
-	r = uint8(col.R*255.0 + 0.5)
-	g = uint8(col.G*255.0 + 0.5)
-	b = uint8(col.B*255.0 + 0.5)
-	a = uint8(col.A*255.0 + 0.5)
+	c := col.ToGamut()
+	r = uint8(c.R*255.0 + 0.5)
+	g = uint8(c.G*255.0 + 0.5)
+	b = uint8(c.B*255.0 + 0.5)
+	a = uint8(c.A*255.0 + 0.5)
 	return
 }
 
@@ -129,7 +197,7 @@ func (col RepaColor) RgbString() string {
 }
 
 func (col RepaColor) HslString() string {
-	h, s, l := col.Hsl()
+	h, s, l := col.Coordinates(CS_HSL)
 	if col.A == 1 {
 		return fmt.Sprintf("hsl(%.3g %s%% %s%%)", h, formatFloat(s*100), formatFloat(l*100))
 	}
@@ -137,7 +205,7 @@ func (col RepaColor) HslString() string {
 }
 
 func (col RepaColor) LabString() string {
-	l, a, b := col.Lab()
+	l, a, b := col.Coordinates(CS_LAB)
 	if col.A == 1 {
 		return fmt.Sprintf("lab(%s%% %s %s)", formatFloat(l*100), formatFloat(a*100), formatFloat(b*100))
 	}
@@ -145,7 +213,7 @@ func (col RepaColor) LabString() string {
 }
 
 func (col RepaColor) LchString() string {
-	h, c, l := col.Hcl()
+	l, c, h := col.Coordinates(CS_LCH)
 	if col.A == 1 {
 		return fmt.Sprintf("lch(%s%% %s %s)", formatFloat(l*100), formatFloat(c*100), formatFloat(h))
 	}
@@ -153,7 +221,7 @@ func (col RepaColor) LchString() string {
 }
 
 func (col RepaColor) OkLabString() string {
-	l, a, b := col.OkLab()
+	l, a, b := col.Coordinates(CS_OKLAB)
 	if col.A == 1 {
 		return fmt.Sprintf("oklab(%s%% %s %s)", formatFloat(l*100), formatFloat(a), formatFloat(b))
 	}
@@ -161,7 +229,7 @@ func (col RepaColor) OkLabString() string {
 }
 
 func (col RepaColor) OkLchString() string {
-	l, c, h := col.OkLch()
+	l, c, h := col.Coordinates(CS_OKLCH)
 	if col.A == 1 {
 		return fmt.Sprintf("oklch(%s%% %s %s)", formatFloat(l*100), formatFloat(c), formatFloat(h))
 	}
@@ -169,15 +237,17 @@ func (col RepaColor) OkLchString() string {
 }
 
 func (col RepaColor) XyzString() string {
-	x, y, z := col.Xyz()
+	x, y, z := col.Coordinates(CS_XYZ)
 	if col.A == 1 {
 		return fmt.Sprintf("xyz(%.4g %.4g %.4g)", x, y, z)
 	}
 	return fmt.Sprintf("xyz(%.4g %.4g %.4g / %s)", x, y, z, formatFloat(col.A))
 }
 
+// Relative luminance as defined by WCAG 2
 func (col RepaColor) Luminance() float64 {
-	return 0.21263900587151036*col.R + 0.71516867876775593*col.G + 0.072192315360733715*col.B
+	r, g, b := col.ToGamut().LinearRgb()
+	return 0.21263900587151036*r + 0.71516867876775593*g + 0.072192315360733715*b
 }
 
 func (col RepaColor) ContrastRatio(c2 RepaColor) float64 {
@@ -189,7 +259,16 @@ func (col RepaColor) ContrastRatio(c2 RepaColor) float64 {
 	return (l2 + 0.05) / (l1 + 0.05)
 }
 
+// Black or white, whichever has the higher contrast with the color,
+// using the algorithm selected by ContrastMethod
 func (col RepaColor) A11YPair() RepaColor {
+	if ContrastMethod == CONTRAST_APCA {
+		if math.Abs(BLACK.APCAContrast(col)) >= math.Abs(WHITE.APCAContrast(col)) {
+			return BLACK
+		}
+		return WHITE
+	}
+
 	// (x + .05) / 0.05 = 1.05 / (x + .05) => 0.179
 	if col.Luminance() > 0.179 {
 		return BLACK
@@ -222,13 +301,16 @@ func (col RepaColor) AlphaBlendRgb(c2 RepaColor, gamma float64) RepaColor {
 		gamma = 2.2
 	}
 
+	col = col.ToGamut()
+	c2 = c2.ToGamut()
+
 	return RepaColor{
-		colorful.Color{
+		Color: colorful.Color{
 			R: math.Pow(math.Pow(col.R, gamma)*a1+math.Pow(c2.R, gamma)*a2*(1-a1), 1/gamma),
 			G: math.Pow(math.Pow(col.G, gamma)*a1+math.Pow(c2.G, gamma)*a2*(1-a1), 1/gamma),
 			B: math.Pow(math.Pow(col.B, gamma)*a1+math.Pow(c2.B, gamma)*a2*(1-a1), 1/gamma),
 		},
-		a,
+		A: a,
 	}
 }
 
@@ -237,23 +319,23 @@ func (col RepaColor) Blend(c2 RepaColor, fraction float64, mode int, useAlpha bo
 
 	switch mode {
 	case BLEND_RGB:
-		retcol = MakeColor(col.BlendRgb(c2.Color, fraction))
+		retcol = RepaColor{Color: col.BlendRgb(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_LINEARRGB:
-		retcol = MakeColor(col.BlendLinearRgb(c2.Color, fraction))
+		retcol = RepaColor{Color: col.BlendLinearRgb(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_HSV:
-		retcol = MakeColor(col.BlendHsv(c2.Color, fraction))
+		retcol = RepaColor{Color: col.BlendHsv(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_LAB:
-		retcol = MakeColor(col.BlendLab(c2.Color, fraction).Clamped())
+		retcol = RepaColor{Color: col.BlendLab(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_LCH:
-		retcol = MakeColor(col.BlendHcl(c2.Color, fraction).Clamped())
+		retcol = RepaColor{Color: col.BlendHcl(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_OKLAB:
-		retcol = MakeColor(col.BlendOkLab(c2.Color, fraction).Clamped())
+		retcol = RepaColor{Color: col.BlendOkLab(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_OKLCH:
-		retcol = MakeColor(col.BlendOkLch(c2.Color, fraction).Clamped())
+		retcol = RepaColor{Color: col.BlendOkLch(c2.Color, fraction), A: 1}.ToGamut()
 	case BLEND_XYZ:
 		x1, y1, z1 := col.Xyz()
 		x2, y2, z2 := c2.Xyz()
-		retcol = MakeColor(colorful.Xyz(x1+(x2-x1)*fraction, y1+(y2-y1)*fraction, z1+(z2-z1)*fraction).Clamped())
+		retcol = RepaColor{Color: colorful.Xyz(x1+(x2-x1)*fraction, y1+(y2-y1)*fraction, z1+(z2-z1)*fraction), A: 1}.ToGamut()
 	}
 
 	if useAlpha {
@@ -268,7 +350,7 @@ func MakeColor(col color.Color) RepaColor {
 	cc, _ := colorful.MakeColor(col)
 
 	return RepaColor{
-		cc,
-		float64(a) / 0xffff,
+		Color: cc,
+		A:     float64(a) / 0xffff,
 	}
 }
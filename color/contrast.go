@@ -0,0 +1,162 @@
+package color
+
+import (
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	CONTRAST_WCAG2 = iota
+	CONTRAST_APCA  = iota
+)
+
+// ContrastMethod selects the algorithm A11YPair uses to choose the text color
+var ContrastMethod = CONTRAST_WCAG2
+
+// WCAG 2 success criterion with its minimum contrast ratio,
+// Lc is the roughly equivalent APCA lightness contrast
+type ContrastLevel struct {
+	Name        string
+	Description string
+	Ratio       float64
+	Lc          float64
+}
+
+var WCAGLevels = []ContrastLevel{
+	{"AA", "AA normal text", 4.5, 60},
+	{"AA-large", "AA large text", 3, 45},
+	{"AAA", "AAA normal text", 7, 75},
+	{"AAA-large", "AAA large text", 4.5, 60},
+	{"UI", "UI components", 3, 45},
+}
+
+func GetWCAGLevel(name string) (ContrastLevel, bool) {
+	for _, level := range WCAGLevels {
+		if strings.EqualFold(level.Name, name) {
+			return level, true
+		}
+	}
+	return ContrastLevel{}, false
+}
+
+// APCA 0.0.98G-4g constants
+const (
+	apcaMainTRC  = 2.4
+	apcaNormBG   = 0.56
+	apcaNormTXT  = 0.57
+	apcaRevTXT   = 0.62
+	apcaRevBG    = 0.65
+	apcaBlkThrs  = 0.022
+	apcaBlkClmp  = 1.414
+	apcaScale    = 1.14
+	apcaLoOffset = 0.027
+	apcaLoClip   = 0.1
+	apcaDeltaMin = 0.0005
+)
+
+// Screen luminance as estimated by APCA
+func (col RepaColor) apcaY() float64 {
+	c := col.ToGamut()
+	y := 0.2126729*math.Pow(c.R, apcaMainTRC) +
+		0.7151522*math.Pow(c.G, apcaMainTRC) +
+		0.0721750*math.Pow(c.B, apcaMainTRC)
+
+	// soft clamp near black
+	if y <= apcaBlkThrs {
+		y += math.Pow(apcaBlkThrs-y, apcaBlkClmp)
+	}
+	return y
+}
+
+// APCA lightness contrast (Lc) of the color used as text on the given
+// background. It is positive for dark text on light background and negative
+// for light text on dark background.
+func (col RepaColor) APCAContrast(bg RepaColor) float64 {
+	ytxt := col.apcaY()
+	ybg := bg.apcaY()
+
+	if math.Abs(ybg-ytxt) < apcaDeltaMin {
+		return 0
+	}
+
+	if ybg > ytxt {
+		sapc := (math.Pow(ybg, apcaNormBG) - math.Pow(ytxt, apcaNormTXT)) * apcaScale
+		if sapc < apcaLoClip {
+			return 0
+		}
+		return (sapc - apcaLoOffset) * 100
+	}
+
+	sapc := (math.Pow(ybg, apcaRevBG) - math.Pow(ytxt, apcaRevTXT)) * apcaScale
+	if sapc > -apcaLoClip {
+		return 0
+	}
+	return (sapc + apcaLoOffset) * 100
+}
+
+// Contrast of the colors with the given method, APCA values are absolute
+func (col RepaColor) Contrast(bg RepaColor, method int) float64 {
+	if method == CONTRAST_APCA {
+		return math.Abs(col.APCAContrast(bg))
+	}
+	return col.ContrastRatio(bg)
+}
+
+// Find the color closest to the original one (by deltaEOK) that reaches the
+// target contrast against the background. Only the OKLCH lightness is
+// changed (chroma is reduced if needed to fit into sRGB), the hue is kept.
+// The target is a contrast ratio for CONTRAST_WCAG2 and an absolute Lc value
+// for CONTRAST_APCA. Translucent colors keep their alpha and are checked as
+// they are seen, composited onto the background. Returns false if the target
+// cannot be reached.
+func (col RepaColor) FixContrast(bg RepaColor, target float64, method int) (RepaColor, bool) {
+	contrast := func(c RepaColor) float64 {
+		if c.A < 1 {
+			c = c.AlphaBlendRgb(bg, 1)
+		}
+		return c.Contrast(bg, method)
+	}
+
+	if contrast(col) >= target {
+		return col, true
+	}
+
+	l, c, h := col.Coordinates(CS_OKLCH)
+	// candidates are rounded to 8 bit sRGB, so the hex form passes as well
+	candidate := func(l float64) RepaColor {
+		r, g, b := CreateColor(CS_OKLCH, l, c, h, col.A).GamutMap().RGB256()
+		return RepaColor{Color: colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}, A: col.A}
+	}
+
+	best := NOCOLOR
+	found := false
+	for _, end := range []float64{0, 1} {
+		if contrast(candidate(end)) < target {
+			continue
+		}
+
+		// binary search for the lightness closest to the original one
+		pass, fail := end, l
+		for i := 0; i < 32; i++ {
+			mid := (pass + fail) / 2
+			if contrast(candidate(mid)) >= target {
+				pass = mid
+			} else {
+				fail = mid
+			}
+		}
+
+		fixed := candidate(pass)
+		if !found || DeltaEOK(col, fixed) < DeltaEOK(col, best) {
+			best = fixed
+			found = true
+		}
+	}
+
+	if !found {
+		return col, false
+	}
+	return best, true
+}
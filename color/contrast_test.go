@@ -0,0 +1,164 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestAPCAContrast(t *testing.T) {
+	tests := []struct {
+		text string
+		bg   string
+		lc   float64
+	}{
+		{"#000000", "#ffffff", 106.04},
+		{"#ffffff", "#000000", -107.88},
+		{"#888888", "#ffffff", 63.06},
+		{"#ffffff", "#888888", -68.54},
+		{"#000000", "#aaaaaa", 58.15},
+		{"#aaaaaa", "#aaaaaa", 0},
+	}
+
+	for _, test := range tests {
+		text, _ := ParseColor(test.text, false)
+		bg, _ := ParseColor(test.bg, false)
+		lc := text.APCAContrast(bg)
+		if !almosteq_eps(lc, test.lc, 0.01) {
+			t.Fatalf("Wrong APCA contrast for %s on %s: %v (vs. %v)", test.text, test.bg, lc, test.lc)
+		}
+	}
+}
+
+func TestA11YPairAPCA(t *testing.T) {
+	defer func() { ContrastMethod = CONTRAST_WCAG2 }()
+	ContrastMethod = CONTRAST_APCA
+
+	if c, _ := ParseColor("#ffffff", false); c.A11YPair() != BLACK {
+		t.Fatalf("Wrong pair for white")
+	}
+	if c, _ := ParseColor("#000080", false); c.A11YPair() != WHITE {
+		t.Fatalf("Wrong pair for navy")
+	}
+	// WCAG 2 prefers black, APCA prefers white
+	c, _ := ParseColor("#ff5000", false)
+	if c.A11YPair() != WHITE {
+		t.Fatalf("Wrong APCA pair for %v", c)
+	}
+	ContrastMethod = CONTRAST_WCAG2
+	if c.A11YPair() != BLACK {
+		t.Fatalf("Wrong WCAG 2 pair for %v", c)
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		c1    string
+		c2    string
+		ratio float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#ffffff", "#000000", 21},
+		{"#888888", "#ffffff", 3.54},
+		{"#777777", "#ffffff", 4.48},
+		{"#0000ff", "#ffffff", 8.59},
+		{"#ff0000", "#00ff00", 2.91},
+		{"#abcdef", "#abcdef", 1},
+	}
+
+	for _, test := range tests {
+		c1, _ := ParseColor(test.c1, false)
+		c2, _ := ParseColor(test.c2, false)
+		ratio := c1.ContrastRatio(c2)
+		if !almosteq_eps(ratio, test.ratio, 0.01) {
+			t.Fatalf("Wrong contrast ratio for %s and %s: %v (vs. %v)", test.c1, test.c2, ratio, test.ratio)
+		}
+	}
+}
+
+func TestGetWCAGLevel(t *testing.T) {
+	if level, ok := GetWCAGLevel("aaa-LARGE"); !ok || level.Ratio != 4.5 {
+		t.Fatalf("Wrong level: %v", level)
+	}
+	if _, ok := GetWCAGLevel("AAAA"); ok {
+		t.Fatalf("Unknown level found")
+	}
+}
+
+func TestFixContrast(t *testing.T) {
+	bgs := []RepaColor{WHITE, BLACK, GRAY, CreateColor(CS_RGB, .2, .3, .8, 1)}
+	for _, bg := range bgs {
+		for _, fgstr := range []string{"#777777", "#ff8000", "oklch(60% 0.15 250)", "#336699"} {
+			fg, _ := ParseColor(fgstr, false)
+			for _, method := range []int{CONTRAST_WCAG2, CONTRAST_APCA} {
+				target := 4.5
+				if method == CONTRAST_APCA {
+					target = 60
+				}
+
+				fixed, ok := fg.FixContrast(bg, target, method)
+				if !ok {
+					continue
+				}
+				if fixed.Contrast(bg, method) < target-0.01 {
+					t.Fatalf("Fixed color %v does not reach %v on %v: %v", fixed, target, bg, fixed.Contrast(bg, method))
+				}
+				if fg.Contrast(bg, method) >= target && fixed != fg {
+					t.Fatalf("Passing color changed: %v => %v", fg, fixed)
+				}
+			}
+		}
+	}
+
+	fg, _ := ParseColor("#777777", false)
+	if _, ok := fg.FixContrast(WHITE, 4.5, CONTRAST_WCAG2); !ok {
+		t.Fatalf("Fix not found for %v", fg)
+	}
+	if _, ok := fg.FixContrast(GRAY, 21, CONTRAST_WCAG2); ok {
+		t.Fatalf("Impossible fix found")
+	}
+}
+
+func TestFixContrastTranslucent(t *testing.T) {
+	// checked as it is seen on the background, the alpha is kept
+	fg, _ := ParseColor("rgb(40 80 200 / .8)", false)
+	fixed, ok := fg.FixContrast(WHITE, 7, CONTRAST_WCAG2)
+	if !ok {
+		t.Fatalf("Fix not found for %v", fg)
+	}
+	if fixed.A != fg.A {
+		t.Fatalf("Alpha changed: %v", fixed.A)
+	}
+	if ratio := fixed.AlphaBlendRgb(WHITE, 1).ContrastRatio(WHITE); ratio < 7 {
+		t.Fatalf("Composited fix %s reaches only %.2f:1", fixed.Hex(), ratio)
+	}
+
+	// half transparent colors are at most #808080 on white (3.95:1)
+	fg, _ = ParseColor("rgb(120 120 120 / .5)", false)
+	if fixed, ok := fg.FixContrast(WHITE, 4.5, CONTRAST_WCAG2); ok {
+		t.Fatalf("Impossible fix found: %s", fixed.Hex())
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"#ff0000":                  "hex",
+		"red":                      "hex",
+		"rgb(255 0 0)":             "rgb",
+		"hsla(0, 100%, 50%, 1)":    "hsla",
+		"oklch(60% 0.1 20)":        "oklch",
+		"color(display-p3 1 0 0)":  "display-p3",
+		"color(srgb 1 0 0)":        "rgb",
+		"color(srgb-linear 1 0 0)": "srgb-linear",
+	}
+
+	for input, format := range tests {
+		if f := DetectFormat(input); f != format {
+			t.Fatalf("Wrong format for %s: %s (vs. %s)", input, f, format)
+		}
+	}
+
+	// the detected format round trips
+	c, _ := ParseColor("color(srgb-linear 0.2 0.5 1 / 0.5)", false)
+	if s, ok := c.FormatAs(DetectFormat("color(srgb-linear 0.2 0.5 1)")); !ok || s != "color(srgb-linear 0.2 0.5 1 / 0.5)" {
+		t.Fatalf("Wrong srgb-linear output: %s", s)
+	}
+}
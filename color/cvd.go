@@ -0,0 +1,173 @@
+package color
+
+import (
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Color vision deficiencies
+const (
+	CVD_PROTAN  = iota
+	CVD_DEUTAN  = iota
+	CVD_TRITAN  = iota
+	CVD_ACHROMA = iota
+)
+
+// Simulation methods
+const (
+	CVD_MACHADO = iota
+	CVD_VIENOT  = iota
+	CVD_BRETTEL = iota
+)
+
+var CVDNames = []string{"protan", "deutan", "tritan", "achroma"}
+var CVDMethodNames = []string{"machado", "vienot", "brettel"}
+
+// Parse the name of a deficiency (protan, protanopia, protanomaly, ...)
+func ParseCVD(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	prefixes := []struct {
+		prefix string
+		kind   int
+	}{
+		{"prot", CVD_PROTAN},
+		{"deut", CVD_DEUTAN},
+		{"trit", CVD_TRITAN},
+		{"achrom", CVD_ACHROMA},
+		{"mono", CVD_ACHROMA},
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.kind, true
+		}
+	}
+	return 0, false
+}
+
+func ParseCVDMethod(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for method, n := range CVDMethodNames {
+		if n == name {
+			return method, true
+		}
+	}
+	return 0, false
+}
+
+// Machado et al. 2009, severity 1.0, linear sRGB
+var machadoMatrices = [3]matrix3{
+	{
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	{
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	{
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// Viénot et al. 1999, dichromats, linear sRGB (no tritan variant)
+var vienotMatrices = [2]matrix3{
+	{
+		{0.11238, 0.88762, 0.00000},
+		{0.11238, 0.88762, -0.00000},
+		{0.00401, -0.00401, 1.00000},
+	},
+	{
+		{0.29275, 0.70725, 0.00000},
+		{0.29275, 0.70725, -0.00000},
+		{-0.02234, 0.02234, 1.00000},
+	},
+}
+
+// Brettel et al. 1997, dichromats, linear sRGB: two projection half-planes
+// separated by a plane through the neutral axis
+type brettelParams struct {
+	h1, h2 matrix3
+	normal [3]float64
+}
+
+var brettelParameters = [3]brettelParams{
+	{
+		h1: matrix3{
+			{0.14980, 1.19548, -0.34528},
+			{0.10764, 0.84864, 0.04372},
+			{0.00384, -0.00540, 1.00156},
+		},
+		h2: matrix3{
+			{0.14570, 1.16172, -0.30742},
+			{0.10816, 0.85291, 0.03892},
+			{0.00386, -0.00524, 1.00139},
+		},
+		normal: [3]float64{0.00048, 0.00393, -0.00441},
+	},
+	{
+		h1: matrix3{
+			{0.36477, 0.86381, -0.22858},
+			{0.26294, 0.64245, 0.09462},
+			{-0.02006, 0.02728, 0.99278},
+		},
+		h2: matrix3{
+			{0.37298, 0.88166, -0.25464},
+			{0.25954, 0.63506, 0.10540},
+			{-0.01980, 0.02784, 0.99196},
+		},
+		normal: [3]float64{-0.00281, -0.00611, 0.00892},
+	},
+	{
+		h1: matrix3{
+			{1.01277, 0.13548, -0.14826},
+			{-0.01243, 0.86812, 0.14431},
+			{0.07589, 0.80500, 0.11911},
+		},
+		h2: matrix3{
+			{0.93678, 0.18979, -0.12657},
+			{0.06154, 0.81526, 0.12320},
+			{-0.37562, 1.12767, 0.24796},
+		},
+		normal: [3]float64{0.03901, -0.02788, -0.01113},
+	},
+}
+
+// Simulate how the color is seen with the given color vision deficiency.
+// Severity goes from 0 (normal vision) to 1 (dichromacy / achromatopsia),
+// partial severities are interpolated between the normal and the fully
+// deficient color. Viénot has no tritan variant, Brettel is used for it.
+func (col RepaColor) SimulateCVD(kind int, severity float64, method int) RepaColor {
+	severity = clamp01(severity)
+	r, g, b := col.ToGamut().LinearRgb()
+
+	var sr, sg, sb float64
+	switch {
+	case kind == CVD_ACHROMA:
+		y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+		sr, sg, sb = y, y, y
+	case kind < CVD_PROTAN || kind > CVD_TRITAN:
+		return col
+	case method == CVD_VIENOT && kind != CVD_TRITAN:
+		sr, sg, sb = vienotMatrices[kind].mul(r, g, b)
+	case method == CVD_BRETTEL || method == CVD_VIENOT:
+		p := brettelParameters[kind]
+		h := p.h1
+		if r*p.normal[0]+g*p.normal[1]+b*p.normal[2] < 0 {
+			h = p.h2
+		}
+		sr, sg, sb = h.mul(r, g, b)
+	default:
+		sr, sg, sb = machadoMatrices[kind].mul(r, g, b)
+	}
+
+	sr = r + (sr-r)*severity
+	sg = g + (sg-g)*severity
+	sb = b + (sb-b)*severity
+
+	return RepaColor{Color: colorful.LinearRgb(clamp01(sr), clamp01(sg), clamp01(sb)), A: col.A}
+}
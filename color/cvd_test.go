@@ -0,0 +1,72 @@
+package color
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCVDNeutralColors(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		v := rand.Float64()
+		c := CreateColor(CS_RGB, v, v, v, 1)
+		for kind := range CVDNames {
+			for method := range CVDMethodNames {
+				s := c.SimulateCVD(kind, 1, method)
+				if !almosteq_eps(s.R, v, 0.01) || !almosteq_eps(s.G, v, 0.01) || !almosteq_eps(s.B, v, 0.01) {
+					t.Fatalf("Gray changed (%s, %s): %v => %v %v %v", CVDNames[kind], CVDMethodNames[method], v, s.R, s.G, s.B)
+				}
+			}
+		}
+	}
+}
+
+func TestCVDSeverity(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		c := CreateColor(CS_RGB, rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64())
+		for kind := range CVDNames {
+			s := c.SimulateCVD(kind, 0, CVD_MACHADO)
+			if !almosteq(c.R, s.R) || !almosteq(c.G, s.G) || !almosteq(c.B, s.B) || c.A != s.A {
+				t.Fatalf("Color changed with 0 severity: %v => %v", c, s)
+			}
+		}
+	}
+}
+
+func TestCVDConfusion(t *testing.T) {
+	red, _ := ParseColor("#d62728", false)
+	green, _ := ParseColor("#2ca02c", false)
+	normal := red.DistanceCIEDE2000(green.Color)
+
+	for _, kind := range []int{CVD_PROTAN, CVD_DEUTAN} {
+		for method := range CVDMethodNames {
+			r := red.SimulateCVD(kind, 1, method)
+			g := green.SimulateCVD(kind, 1, method)
+			if d := r.DistanceCIEDE2000(g.Color); d > normal/2 {
+				t.Fatalf("Red and green should be confused (%s, %s): %v vs. %v", CVDNames[kind], CVDMethodNames[method], d, normal)
+			}
+		}
+	}
+
+	s := red.SimulateCVD(CVD_ACHROMA, 1, CVD_MACHADO)
+	if !almosteq(s.R, s.G) || !almosteq(s.G, s.B) {
+		t.Fatalf("Achromatopsia should give gray: %v", s)
+	}
+}
+
+func TestParseCVD(t *testing.T) {
+	tests := map[string]int{
+		"protan":        CVD_PROTAN,
+		"Deuteranomaly": CVD_DEUTAN,
+		"tritanopia":    CVD_TRITAN,
+		"achromatopsia": CVD_ACHROMA,
+		"monochromacy":  CVD_ACHROMA,
+	}
+	for name, kind := range tests {
+		if k, ok := ParseCVD(name); !ok || k != kind {
+			t.Fatalf("Wrong deficiency for %s: %v", name, k)
+		}
+	}
+	if _, ok := ParseCVD("foo"); ok {
+		t.Fatalf("Unknown deficiency parsed")
+	}
+}
@@ -0,0 +1,46 @@
+package color
+
+import (
+	"strings"
+)
+
+const (
+	EASE_LINEAR     = iota
+	EASE_IN         = iota
+	EASE_OUT        = iota
+	EASE_IN_OUT     = iota
+	EASE_SMOOTHSTEP = iota
+)
+
+var EasingNames = []string{"linear", "ease-in", "ease-out", "ease-in-out", "smoothstep"}
+
+func ParseEasing(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for easing, n := range EasingNames {
+		if n == name {
+			return easing, true
+		}
+	}
+	return 0, false
+}
+
+// Apply the easing function to t (0-1)
+func Ease(easing int, t float64) float64 {
+	t = clamp01(t)
+
+	switch easing {
+	case EASE_IN:
+		return t * t
+	case EASE_OUT:
+		return 1 - (1-t)*(1-t)
+	case EASE_IN_OUT:
+		if t < .5 {
+			return 2 * t * t
+		}
+		return 1 - 2*(1-t)*(1-t)
+	case EASE_SMOOTHSTEP:
+		return t * t * (3 - 2*t)
+	}
+
+	return t
+}
@@ -0,0 +1,356 @@
+package color
+
+import (
+	"image"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Dominant colors of images. The pixels are collected into a histogram of
+// 15 bit colors (their mean color is kept), then the histogram is clustered
+// in OKLab with k-means or median cut.
+
+const (
+	EXTRACT_KMEANS    = iota
+	EXTRACT_MEDIANCUT = iota
+)
+
+var ExtractMethodNames = []string{"kmeans", "median-cut"}
+
+// Weighting of the pixels
+const (
+	// every pixel counts the same
+	WEIGHT_COUNT = iota
+	// saturated colors count more, so accents of logos and screenshots
+	// aren't lost among the large neutral areas
+	WEIGHT_CHROMA = iota
+	// pixels in the center of the image count more than the ones at the edges
+	WEIGHT_CENTER = iota
+)
+
+var ExtractWeightNames = []string{"count", "chroma", "center"}
+
+type ExtractOptions struct {
+	// number of colors (6 if not given)
+	Count  int
+	Method int
+	// pixel weighting
+	Weighting int
+	// pixels with lower alpha are ignored, the others are weighted by their
+	// alpha
+	AlphaThreshold float64
+}
+
+// Extracted color with the share of the (weighted) pixels it represents
+type Swatch struct {
+	Color  RepaColor
+	Weight float64
+}
+
+// pixels sampled from large images
+const extractMaxSamples = 250000
+
+// k-means iteration limit
+const kmeansMaxIterations = 100
+
+func ParseExtractMethod(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "k-means":
+		return EXTRACT_KMEANS, true
+	case "mediancut", "median":
+		return EXTRACT_MEDIANCUT, true
+	}
+	for method, n := range ExtractMethodNames {
+		if n == name {
+			return method, true
+		}
+	}
+	return 0, false
+}
+
+func ParseExtractWeight(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for weighting, n := range ExtractWeightNames {
+		if n == name {
+			return weighting, true
+		}
+	}
+	return 0, false
+}
+
+// Histogram bin, a cluster of similar pixels
+type extractBin struct {
+	weight float64
+	// weighted sum of the 8 bit values while collecting, then the mean
+	r, g, b float64
+	lab     [3]float64
+}
+
+// Weighted histogram of the image pixels
+func extractHistogram(img image.Image, opts ExtractOptions) []extractBin {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	step := 1
+	if pixels := width * height; pixels > extractMaxSamples {
+		step = int(math.Ceil(math.Sqrt(float64(pixels) / extractMaxSamples)))
+	}
+
+	bins := map[int]*extractBin{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			alpha := float64(a) / 0xffff
+			if a == 0 || alpha < opts.AlphaThreshold {
+				continue
+			}
+			// unpremultiplied 8 bit values
+			rf, gf, bf := float64(r)/float64(a)*255, float64(g)/float64(a)*255, float64(b)/float64(a)*255
+
+			weight := alpha
+			if opts.Weighting == WEIGHT_CENTER {
+				dx := (float64(x-bounds.Min.X)+.5)/float64(width)*2 - 1
+				dy := (float64(y-bounds.Min.Y)+.5)/float64(height)*2 - 1
+				weight *= 1 - .75*(dx*dx+dy*dy)/2
+			}
+
+			key := int(rf)>>3<<10 | int(gf)>>3<<5 | int(bf)>>3
+			bin, ok := bins[key]
+			if !ok {
+				bin = &extractBin{}
+				bins[key] = bin
+			}
+			bin.weight += weight
+			bin.r += rf * weight
+			bin.g += gf * weight
+			bin.b += bf * weight
+		}
+	}
+
+	// sorted by key, so the results don't depend on the map order
+	keys := make([]int, 0, len(bins))
+	for key := range bins {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	histogram := make([]extractBin, 0, len(bins))
+	for _, key := range keys {
+		bin := *bins[key]
+		bin.r, bin.g, bin.b = bin.r/bin.weight, bin.g/bin.weight, bin.b/bin.weight
+		c := CreateColor(CS_RGB, bin.r/255, bin.g/255, bin.b/255, 1)
+		bin.lab[0], bin.lab[1], bin.lab[2] = c.Coordinates(CS_OKLAB)
+		if opts.Weighting == WEIGHT_CHROMA {
+			bin.weight *= 1 + 10*math.Hypot(bin.lab[1], bin.lab[2])
+		}
+		histogram = append(histogram, bin)
+	}
+	return histogram
+}
+
+func labDistance2(p, q [3]float64) float64 {
+	dl, da, db := p[0]-q[0], p[1]-q[1], p[2]-q[2]
+	return dl*dl + da*da + db*db
+}
+
+// Weighted mean of the bins
+func meanLab(bins []extractBin) ([3]float64, float64) {
+	var mean [3]float64
+	weight := 0.0
+	for _, bin := range bins {
+		for i := range mean {
+			mean[i] += bin.lab[i] * bin.weight
+		}
+		weight += bin.weight
+	}
+	if weight > 0 {
+		for i := range mean {
+			mean[i] /= weight
+		}
+	}
+	return mean, weight
+}
+
+// k-means, initialized with the heaviest bin and then the bins with the
+// largest weighted distance from the centroids (deterministic k-means++)
+func extractKMeans(bins []extractBin, count int) []Swatch {
+	var centroids [][3]float64
+	heaviest := 0
+	for i, bin := range bins {
+		if bin.weight > bins[heaviest].weight {
+			heaviest = i
+		}
+	}
+	centroids = append(centroids, bins[heaviest].lab)
+
+	nearest := make([]float64, len(bins))
+	for i, bin := range bins {
+		nearest[i] = labDistance2(bin.lab, centroids[0])
+	}
+	for len(centroids) < count {
+		next, score := -1, 0.0
+		for i, bin := range bins {
+			if s := bin.weight * nearest[i]; s > score {
+				next, score = i, s
+			}
+		}
+		if next < 0 {
+			// fewer distinct colors than requested
+			break
+		}
+		centroids = append(centroids, bins[next].lab)
+		for i, bin := range bins {
+			nearest[i] = math.Min(nearest[i], labDistance2(bin.lab, bins[next].lab))
+		}
+	}
+
+	assignment := make([]int, len(bins))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	var weights []float64
+	for iteration := 0; iteration < kmeansMaxIterations; iteration++ {
+		changed := false
+		for i, bin := range bins {
+			best := 0
+			for j := range centroids {
+				if labDistance2(bin.lab, centroids[j]) < labDistance2(bin.lab, centroids[best]) {
+					best = j
+				}
+			}
+			if assignment[i] != best {
+				assignment[i] = best
+				changed = true
+			}
+		}
+
+		sums := make([][3]float64, len(centroids))
+		weights = make([]float64, len(centroids))
+		for i, bin := range bins {
+			for k := range sums[assignment[i]] {
+				sums[assignment[i]][k] += bin.lab[k] * bin.weight
+			}
+			weights[assignment[i]] += bin.weight
+		}
+		for j := range centroids {
+			if weights[j] > 0 {
+				for k := range centroids[j] {
+					centroids[j][k] = sums[j][k] / weights[j]
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	var swatches []Swatch
+	for j, centroid := range centroids {
+		if weights[j] > 0 {
+			swatches = append(swatches, Swatch{labSwatchColor(centroid, bins), weights[j]})
+		}
+	}
+	return swatches
+}
+
+// Median cut: the box with the largest weighted extent is split at the
+// weighted median of its longest axis, until there are enough boxes
+func extractMedianCut(bins []extractBin, count int) []Swatch {
+	boxes := [][]extractBin{bins}
+
+	extent := func(box []extractBin) (int, float64) {
+		axis, size := 0, 0.0
+		for k := 0; k < 3; k++ {
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, bin := range box {
+				lo = math.Min(lo, bin.lab[k])
+				hi = math.Max(hi, bin.lab[k])
+			}
+			if hi-lo > size {
+				axis, size = k, hi-lo
+			}
+		}
+		return axis, size
+	}
+
+	for len(boxes) < count {
+		split, score, splitAxis := -1, 0.0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			axis, size := extent(box)
+			_, weight := meanLab(box)
+			if s := size * weight; s > score {
+				split, score, splitAxis = i, s, axis
+			}
+		}
+		if split < 0 {
+			break
+		}
+
+		box := boxes[split]
+		sort.SliceStable(box, func(i, j int) bool { return box[i].lab[splitAxis] < box[j].lab[splitAxis] })
+		_, total := meanLab(box)
+		median, acc := 1, box[0].weight
+		for median < len(box)-1 && acc+box[median].weight <= total/2 {
+			acc += box[median].weight
+			median++
+		}
+		boxes = append(boxes, box[median:])
+		boxes[split] = box[:median]
+	}
+
+	swatches := make([]Swatch, 0, len(boxes))
+	for _, box := range boxes {
+		mean, weight := meanLab(box)
+		swatches = append(swatches, Swatch{labSwatchColor(mean, box), weight})
+	}
+	return swatches
+}
+
+// Color of a cluster. Flat areas (logos, screenshots) keep their exact sRGB
+// color, instead of the OKLab round trip of it.
+func labSwatchColor(lab [3]float64, bins []extractBin) RepaColor {
+	c := CreateColor(CS_OKLAB, lab[0], lab[1], lab[2], 1)
+	for _, bin := range bins {
+		if labDistance2(bin.lab, lab) < 1e-6 {
+			c = CreateColor(CS_RGB, bin.r/255, bin.g/255, bin.b/255, 1)
+			break
+		}
+	}
+	r, g, b := c.GamutMap().RGB256()
+	return CreateColor(CS_RGB, float64(r)/255, float64(g)/255, float64(b)/255, 1)
+}
+
+// Dominant colors of the image, ordered by their weight (the share of the
+// pixels they represent, 0-1). Fully transparent images have no colors.
+func ExtractPalette(img image.Image, opts ExtractOptions) []Swatch {
+	if opts.Count <= 0 {
+		opts.Count = 6
+	}
+
+	bins := extractHistogram(img, opts)
+	if len(bins) == 0 {
+		return nil
+	}
+
+	var swatches []Swatch
+	if opts.Method == EXTRACT_MEDIANCUT {
+		swatches = extractMedianCut(bins, opts.Count)
+	} else {
+		swatches = extractKMeans(bins, opts.Count)
+	}
+
+	total := 0.0
+	for _, s := range swatches {
+		total += s.Weight
+	}
+	for i := range swatches {
+		swatches[i].Weight /= total
+	}
+	sort.SliceStable(swatches, func(i, j int) bool { return swatches[i].Weight > swatches[j].Weight })
+	return swatches
+}
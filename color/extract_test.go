@@ -0,0 +1,123 @@
+package color
+
+import (
+	"image"
+	imgcolor "image/color"
+	"testing"
+)
+
+// 100x100 image: 60% red, 30% blue, 10% semi-transparent green
+func extractTestImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			switch {
+			case y < 60:
+				img.Set(x, y, imgcolor.NRGBA{255, 0, 0, 255})
+			case y < 90:
+				img.Set(x, y, imgcolor.NRGBA{0, 0, 255, 255})
+			default:
+				img.Set(x, y, imgcolor.NRGBA{0, 255, 0, 64})
+			}
+		}
+	}
+	return img
+}
+
+func TestExtractPalette(t *testing.T) {
+	img := extractTestImage()
+	for method := range ExtractMethodNames {
+		swatches := ExtractPalette(img, ExtractOptions{Count: 6, Method: method, AlphaThreshold: .5})
+		if len(swatches) != 2 {
+			t.Fatalf("%s: expected 2 colors (transparent pixels ignored), got %v", ExtractMethodNames[method], swatches)
+		}
+		if swatches[0].Color.Hex() != "#ff0000" || swatches[1].Color.Hex() != "#0000ff" {
+			t.Errorf("%s: wrong colors: %s %s", ExtractMethodNames[method], swatches[0].Color.Hex(), swatches[1].Color.Hex())
+		}
+		if !almosteq_eps(swatches[0].Weight, 2./3, .001) || !almosteq_eps(swatches[1].Weight, 1./3, .001) {
+			t.Errorf("%s: wrong weights: %v %v", ExtractMethodNames[method], swatches[0].Weight, swatches[1].Weight)
+		}
+	}
+
+	// semi-transparent pixels are weighted by their alpha
+	swatches := ExtractPalette(img, ExtractOptions{Count: 3})
+	if len(swatches) != 3 || swatches[2].Color.Hex() != "#00ff00" {
+		t.Fatalf("Expected green as the third color: %v", swatches)
+	}
+	if w := 10 * 64. / 255 / (90 + 10*64./255); !almosteq_eps(swatches[2].Weight, w, .001) {
+		t.Errorf("Wrong weight of semi-transparent green: %v, expected %v", swatches[2].Weight, w)
+	}
+}
+
+func TestExtractClusters(t *testing.T) {
+	// two groups of similar colors
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 40; x++ {
+			v := uint8(x % 10 * 2)
+			if x < 20 {
+				img.Set(x, y, imgcolor.NRGBA{200 + v, 40 + v, 40, 255})
+			} else {
+				img.Set(x, y, imgcolor.NRGBA{40, 40 + v, 200 + v, 255})
+			}
+		}
+	}
+
+	for method := range ExtractMethodNames {
+		swatches := ExtractPalette(img, ExtractOptions{Count: 2, Method: method})
+		if len(swatches) != 2 {
+			t.Fatalf("%s: expected 2 colors, got %v", ExtractMethodNames[method], swatches)
+		}
+		red := CreateColor(CS_RGB, 209./255, 49./255, 40./255, 1)
+		blue := CreateColor(CS_RGB, 40./255, 49./255, 209./255, 1)
+		found := 0
+		for _, s := range swatches {
+			if DeltaEOK(s.Color, red) < .02 || DeltaEOK(s.Color, blue) < .02 {
+				found++
+			}
+		}
+		if found != 2 {
+			t.Errorf("%s: clusters not found: %s %s", ExtractMethodNames[method], swatches[0].Color.Hex(), swatches[1].Color.Hex())
+		}
+	}
+}
+
+func TestExtractWeighting(t *testing.T) {
+	// gray background with a small saturated square in the center
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			if x >= 8 && x < 12 && y >= 8 && y < 12 {
+				img.Set(x, y, imgcolor.NRGBA{255, 0, 128, 255})
+			} else {
+				img.Set(x, y, imgcolor.NRGBA{128, 128, 128, 255})
+			}
+		}
+	}
+
+	count := ExtractPalette(img, ExtractOptions{Count: 2})
+	for _, weighting := range []int{WEIGHT_CHROMA, WEIGHT_CENTER} {
+		weighted := ExtractPalette(img, ExtractOptions{Count: 2, Weighting: weighting})
+		if weighted[1].Weight <= count[1].Weight {
+			t.Errorf("%s: accent weight not increased: %v <= %v", ExtractWeightNames[weighting], weighted[1].Weight, count[1].Weight)
+		}
+	}
+}
+
+func TestExtractTransparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	if swatches := ExtractPalette(img, ExtractOptions{}); len(swatches) != 0 {
+		t.Errorf("Colors extracted from a transparent image: %v", swatches)
+	}
+}
+
+func TestParseExtractMethod(t *testing.T) {
+	for name, expected := range map[string]int{"kmeans": EXTRACT_KMEANS, " K-Means": EXTRACT_KMEANS, "median-cut": EXTRACT_MEDIANCUT, "mediancut": EXTRACT_MEDIANCUT} {
+		if method, ok := ParseExtractMethod(name); !ok || method != expected {
+			t.Errorf("ParseExtractMethod(%q) = %v, %v", name, method, ok)
+		}
+	}
+	if _, ok := ParseExtractMethod("octree"); ok {
+		t.Error("Unknown method parsed")
+	}
+}
@@ -0,0 +1,91 @@
+package color
+
+import (
+	"strings"
+)
+
+// Format the color in the given output format, returns false for unknown
+// formats
+func (col RepaColor) FormatAs(format string) (string, bool) {
+	switch strings.ToLower(format) {
+	case "hex":
+		return col.Hex(), true
+	case "rgb", "rgba":
+		return col.RgbString(), true
+	case "hsl", "hsla":
+		return col.HslString(), true
+	case "lab":
+		return col.LabString(), true
+	case "lch":
+		return col.LchString(), true
+	case "oklab":
+		return col.OkLabString(), true
+	case "oklch":
+		return col.OkLchString(), true
+	case "xyz":
+		return col.XyzString(), true
+	case "display-p3", "p3":
+		return col.DisplayP3String(), true
+	case "rec2020":
+		return col.Rec2020String(), true
+	case "a98-rgb", "a98":
+		return col.A98RgbString(), true
+	case "prophoto-rgb", "prophoto":
+		return col.ProPhotoRgbString(), true
+	case "srgb-linear":
+		return col.LinearRgbString(), true
+	}
+
+	return "", false
+}
+
+// Guess the format of a color string, so output can be given in the same
+// format as the input. Falls back to hex.
+func DetectFormat(cstr string) string {
+	s := strings.ToLower(strings.TrimSpace(cstr))
+
+	if name, args, ok := splitFunction(s); ok {
+		switch name {
+		case "rgb", "rgba", "hsl", "hsla", "lab", "lch", "oklab", "oklch":
+			return name
+		case "color":
+			comps, _, _ := splitComponents(args)
+			if len(comps) > 0 {
+				switch comps[0] {
+				case "srgb":
+					return "rgb"
+				case "xyz", "xyz-d65":
+					return "xyz"
+				}
+				if _, ok := (RepaColor{}).FormatAs(comps[0]); ok {
+					return comps[0]
+				}
+			}
+		}
+	}
+
+	return "hex"
+}
+
+var colorSpaceNames = map[string]int{
+	"rgb":          CS_RGB,
+	"srgb":         CS_RGB,
+	"srgb-linear":  CS_LINEARRGB,
+	"hsl":          CS_HSL,
+	"lab":          CS_LAB,
+	"lch":          CS_LCH,
+	"hcl":          CS_HCL,
+	"oklab":        CS_OKLAB,
+	"oklch":        CS_OKLCH,
+	"xyz":          CS_XYZ,
+	"display-p3":   CS_DISPLAYP3,
+	"rec2020":      CS_REC2020,
+	"a98-rgb":      CS_A98RGB,
+	"prophoto-rgb": CS_PROPHOTO,
+}
+
+// Parse the name of a color space into its CS_* constant
+func ParseColorSpace(name string) (int, bool) {
+	mode, ok := colorSpaceNames[strings.ToLower(strings.TrimSpace(name))]
+	return mode, ok
+}
@@ -0,0 +1,173 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Where a parsed color comes from
+const (
+	SOURCE_CSS      = iota
+	SOURCE_NAME     = iota
+	SOURCE_FALLBACK = iota
+)
+
+// Parsed color with the details of how the input was interpreted
+type ColorInfo struct {
+	Color  RepaColor
+	Source int
+	// matched name and its dictionary (SOURCE_NAME)
+	Name       string
+	Dictionary string
+	// modifiers applied to the named color (light, dark, pale, deep)
+	Modifiers []string
+	// edit distance between the input and the name
+	Edits int
+}
+
+// Human readable description of the interpretation
+func (info ColorInfo) Description() string {
+	switch info.Source {
+	case SOURCE_NAME:
+		desc := fmt.Sprintf("%s name \"%s\"", info.Dictionary, info.Name)
+		if info.Edits == 1 {
+			desc += " (1 typo)"
+		} else if info.Edits > 1 {
+			desc += fmt.Sprintf(" (%d typos)", info.Edits)
+		}
+		if len(info.Modifiers) > 0 {
+			desc = strings.Join(info.Modifiers, " ") + " " + desc
+		}
+		return desc
+	case SOURCE_FALLBACK:
+		return "unknown color, generated from the hash of the input"
+	}
+	return ""
+}
+
+// Lightness and chroma changes of the modifiers in OKLCH
+var nameModifiers = map[string]func(l, c float64) (float64, float64){
+	"light": func(l, c float64) (float64, float64) { return l + (1-l)*.35, c },
+	"dark":  func(l, c float64) (float64, float64) { return l * .65, c },
+	"pale":  func(l, c float64) (float64, float64) { return l + (1-l)*.4, c * .5 },
+	"deep":  func(l, c float64) (float64, float64) { return l * .8, c * 1.2 },
+}
+
+// Normalized form of a name for matching: lowercase letters and digits only
+func nameKey(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Only letters, digits, spaces and a few punctuation marks can be names
+func looksLikeName(s string) bool {
+	hasLetter := false
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r), unicode.IsSpace(r), r == '-', r == '_', r == '\'':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Closest name in the dictionaries (in the order of NameDictionaries) within
+// a length dependent number of edits
+func fuzzyName(key string) (ColorInfo, bool) {
+	maxEdits := min((len(key)+1)/5, 3)
+
+	best := ColorInfo{Edits: maxEdits + 1}
+	for _, d := range NameDictionaries {
+		dict, err := GetDictionary(d)
+		if err != nil {
+			continue
+		}
+		for _, nc := range dict {
+			nkey := nameKey(nc.Name)
+			if diff := len(nkey) - len(key); diff > maxEdits || -diff > maxEdits {
+				continue
+			}
+
+			edits := levenshtein(key, nkey)
+			if edits < best.Edits {
+				best = ColorInfo{Color: nc.Color, Source: SOURCE_NAME, Name: nc.Name, Dictionary: d, Edits: edits}
+				if edits == 0 {
+					return best, true
+				}
+			}
+		}
+	}
+
+	return best, best.Edits <= maxEdits
+}
+
+// Match the input against the named colors, allowing typos and modifiers
+// like "light", "dark" or "pale" in front of the name
+func matchColorName(cstr string) (ColorInfo, bool) {
+	if !looksLikeName(cstr) {
+		return ColorInfo{}, false
+	}
+
+	if info, ok := fuzzyName(nameKey(cstr)); ok {
+		return info, true
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(cstr), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	var modifiers []string
+	for len(words) > 1 && nameModifiers[words[0]] != nil {
+		modifiers = append(modifiers, words[0])
+		words = words[1:]
+	}
+	if len(modifiers) == 0 {
+		return ColorInfo{}, false
+	}
+
+	info, ok := fuzzyName(nameKey(strings.Join(words, "")))
+	if !ok {
+		return ColorInfo{}, false
+	}
+
+	l, c, h := info.Color.OkLch()
+	// the closest modifier is applied last
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		l, c = nameModifiers[modifiers[i]](l, c)
+	}
+	info.Color = CreateColor(CS_OKLCH, clamp01(l), c, h, info.Color.A).GamutMap()
+	info.Modifiers = modifiers
+
+	return info, true
+}
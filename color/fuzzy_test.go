@@ -0,0 +1,81 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestParseFuzzyNames(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+		edits int
+	}{
+		{"dark sea gren", "darkseagreen", 1},
+		{"Dark-Sea-Green", "darkseagreen", 0},
+		{"cornflwer blu", "cornflowerblue", 2},
+		{"burnt sienna", "burnt sienna", 0},
+		{"tickle me pink", "tickle me pink", 0},
+	}
+
+	for _, test := range tests {
+		info, err := ParseColorInfo(test.input, nil, true)
+		if err != nil {
+			t.Fatalf("%s: %v", test.input, err)
+		}
+		if info.Source != SOURCE_NAME || info.Name != test.name || info.Edits != test.edits {
+			t.Fatalf("Wrong match for %s: %v", test.input, info)
+		}
+	}
+}
+
+func TestParseNameModifiers(t *testing.T) {
+	teal, _ := ParseColor("teal", false)
+	tl, tc, _ := teal.OkLch()
+
+	info, err := ParseColorInfo("pale teal", nil, true)
+	if err != nil || len(info.Modifiers) != 1 || info.Modifiers[0] != "pale" {
+		t.Fatalf("Wrong match: %v %v", info, err)
+	}
+	l, c, _ := info.Color.OkLch()
+	if l <= tl || c >= tc {
+		t.Fatalf("Pale color should be lighter and less saturated")
+	}
+
+	info, err = ParseColorInfo("dark dark teel", nil, true)
+	if err != nil || len(info.Modifiers) != 2 || info.Edits != 1 {
+		t.Fatalf("Wrong match: %v %v", info, err)
+	}
+	if l, _, _ := info.Color.OkLch(); l >= tl*.65 {
+		t.Fatalf("Dark dark color should be darker")
+	}
+}
+
+func TestParseColorInfo(t *testing.T) {
+	info, err := ParseColorInfo("#ff0000", nil, false)
+	if err != nil || info.Source != SOURCE_CSS || info.Description() != "" {
+		t.Fatalf("Wrong info: %v %v", info, err)
+	}
+
+	// names are only matched where the fallback would be used
+	for _, input := range []string{"foo", "qwertyuiop", "rgb(1 2)", "#12", "bold", "reed", "dark sea gren", "pale teal"} {
+		if _, err := ParseColorInfo(input, nil, false); err == nil {
+			t.Fatalf("%s should fail", input)
+		}
+	}
+
+	info, err = ParseColorInfo("qwertyuiop", nil, true)
+	if err != nil || info.Source != SOURCE_FALLBACK {
+		t.Fatalf("Fallback should be used: %v %v", info, err)
+	}
+}
+
+func TestParseStrictNames(t *testing.T) {
+	for _, input := range []string{"bold", "reed"} {
+		if c, err := ParseColor(input, false); err == nil {
+			t.Errorf("%s should fail without fallback, got %s", input, c.Hex())
+		}
+		if info, err := ParseColorInfo(input, nil, true); err != nil || info.Source != SOURCE_NAME {
+			t.Errorf("%s should match a name with fallback: %v %v", input, info, err)
+		}
+	}
+}
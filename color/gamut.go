@@ -0,0 +1,108 @@
+package color
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	GAMUT_CLIP = iota
+	GAMUT_CSS4 = iota
+)
+
+// CSS Color 4 gamut mapping constants
+const GAMUT_JND = 0.02
+const GAMUT_EPSILON = 0.0001
+
+// tolerance used when deciding if a color is inside the sRGB gamut
+const gamutTolerance = 0.000075
+
+// GamutMethod selects how out of gamut colors are brought into sRGB when
+// they are rendered or blended.
+var GamutMethod = GAMUT_CLIP
+
+// Returns true if the color can be displayed in sRGB without clipping
+func (col RepaColor) InGamut() bool {
+	return inGamut(col.Color)
+}
+
+func inGamut(c colorful.Color) bool {
+	return c.R >= -gamutTolerance && c.R <= 1+gamutTolerance &&
+		c.G >= -gamutTolerance && c.G <= 1+gamutTolerance &&
+		c.B >= -gamutTolerance && c.B <= 1+gamutTolerance
+}
+
+// Clip the color into the sRGB gamut channel by channel
+func (col RepaColor) Clip() RepaColor {
+	return RepaColor{Color: col.Clamped(), A: col.A}
+}
+
+// Distance of the colors in OKLab (deltaEOK)
+func DeltaEOK(c1, c2 RepaColor) float64 {
+	return deltaEOK(c1.Color, c2.Color)
+}
+
+func deltaEOK(c1, c2 colorful.Color) float64 {
+	l1, a1, b1 := c1.OkLab()
+	l2, a2, b2 := c2.OkLab()
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// Map the color into the sRGB gamut using the CSS Color 4 algorithm:
+// reduce the OKLCH chroma until the clipped color is within JND of it.
+// https://www.w3.org/TR/css-color-4/#binsearch
+func (col RepaColor) GamutMap() RepaColor {
+	if col.InGamut() {
+		return col.Clip()
+	}
+
+	l, c, h := col.OkLch()
+	if l >= 1 {
+		return RepaColor{Color: WHITE.Color, A: col.A}
+	}
+	if l <= 0 {
+		return RepaColor{Color: BLACK.Color, A: col.A}
+	}
+
+	current := col.Color
+	clipped := current.Clamped()
+	if deltaEOK(clipped, current) < GAMUT_JND {
+		return RepaColor{Color: clipped, A: col.A}
+	}
+
+	min := 0.0
+	max := c
+	minInGamut := true
+	for max-min > GAMUT_EPSILON {
+		chroma := (min + max) / 2
+		current = colorful.OkLch(l, chroma, h)
+
+		if minInGamut && inGamut(current) {
+			min = chroma
+			continue
+		}
+
+		clipped = current.Clamped()
+		e := deltaEOK(clipped, current)
+		if e < GAMUT_JND {
+			if GAMUT_JND-e < GAMUT_EPSILON {
+				break
+			}
+			minInGamut = false
+			min = chroma
+		} else {
+			max = chroma
+		}
+	}
+
+	return RepaColor{Color: clipped, A: col.A}
+}
+
+// Bring the color into the sRGB gamut using the method set in GamutMethod
+func (col RepaColor) ToGamut() RepaColor {
+	if GamutMethod == GAMUT_CSS4 {
+		return col.GamutMap()
+	}
+	return col.Clip()
+}
@@ -0,0 +1,62 @@
+package color
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInGamut(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		c := CreateColor(CS_RGB, rand.Float64(), rand.Float64(), rand.Float64(), 1)
+		if !c.InGamut() {
+			t.Fatalf("sRGB color should be in gamut: %v", c)
+		}
+	}
+
+	c := CreateColor(CS_OKLCH, .7, .4, 150, 1)
+	if c.InGamut() {
+		t.Fatalf("Color should be out of gamut: %v %v %v", c.R, c.G, c.B)
+	}
+}
+
+func TestGamutMapInGamut(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		c := CreateColor(CS_RGB, rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64())
+		m := c.GamutMap()
+		if !almosteq(c.R, m.R) || !almosteq(c.G, m.G) || !almosteq(c.B, m.B) || c.A != m.A {
+			t.Fatalf("In gamut color changed: %v => %v", c, m)
+		}
+	}
+}
+
+func TestGamutMapOutOfGamut(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		l := .1 + rand.Float64()*.8
+		h := rand.Float64() * 360
+		c := CreateColor(CS_OKLCH, l, .4, h, 1)
+		m := c.GamutMap()
+		if !m.InGamut() {
+			t.Fatalf("Mapped color is out of gamut: %v %v %v", m.R, m.G, m.B)
+		}
+
+		ml, mc, _ := m.OkLch()
+		if !almosteq_eps(l, ml, GAMUT_JND) {
+			t.Fatalf("Lightness changed too much: %v => %v", l, ml)
+		}
+		if d := DeltaEOK(m, CreateColor(CS_OKLCH, l, mc, h, 1)); d > 2*GAMUT_JND {
+			t.Fatalf("Hue changed too much: %v (deltaEOK: %v)", h, d)
+		}
+		if mc > .4 {
+			t.Fatalf("Chroma increased: %v", mc)
+		}
+	}
+}
+
+func TestGamutMapExtremes(t *testing.T) {
+	if c := CreateColor(CS_OKLCH, 1.2, .3, 100, 1).GamutMap(); c.Hex() != "#ffffff" {
+		t.Fatalf("Too light color should map to white: %v", c)
+	}
+	if c := CreateColor(CS_OKLCH, -.1, .3, 100, 1).GamutMap(); c.Hex() != "#000000" {
+		t.Fatalf("Too dark color should map to black: %v", c)
+	}
+}
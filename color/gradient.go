@@ -0,0 +1,248 @@
+package color
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// CSS Color 4 hue interpolation methods
+const (
+	HUE_SHORTER    = iota
+	HUE_LONGER     = iota
+	HUE_INCREASING = iota
+	HUE_DECREASING = iota
+)
+
+var HueMethodNames = []string{"shorter", "longer", "increasing", "decreasing"}
+
+func ParseHueMethod(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for method, n := range HueMethodNames {
+		if n == name {
+			return method, true
+		}
+	}
+	return 0, false
+}
+
+// Index of the hue in the coordinates of the color space, -1 for non polar spaces
+func hueIndex(mode int) int {
+	switch mode {
+	case CS_HSL, CS_HCL:
+		return 0
+	case CS_LCH, CS_OKLCH:
+		return 2
+	}
+	return -1
+}
+
+// Index of the saturation / chroma, used to find powerless hues
+func chromaIndex(mode int) int {
+	switch mode {
+	case CS_HSL, CS_HCL, CS_LCH, CS_OKLCH:
+		return 1
+	}
+	return -1
+}
+
+// below this chroma the hue is powerless (white has a tiny OKLCH chroma due to
+// rounding in the conversion matrices)
+const achromaticThreshold = 5e-4
+
+// Adjust the hues for the interpolation method
+func fixupHues(h1, h2 float64, method int) (float64, float64) {
+	d := h2 - h1
+	switch method {
+	case HUE_LONGER:
+		if d > 0 && d < 180 {
+			h1 += 360
+		} else if d > -180 && d <= 0 {
+			h2 += 360
+		}
+	case HUE_INCREASING:
+		if h2 < h1 {
+			h2 += 360
+		}
+	case HUE_DECREASING:
+		if h1 < h2 {
+			h1 += 360
+		}
+	default:
+		if d > 180 {
+			h1 += 360
+		} else if d < -180 {
+			h2 += 360
+		}
+	}
+	return h1, h2
+}
+
+// Interpolate between two colors in the given color space (CS_*) the CSS
+// Color 4 way: with premultiplied alpha, powerless hues taking the hue of the
+// other color, and the given hue interpolation method for polar spaces.
+func Interpolate(c1, c2 RepaColor, t float64, mode int, hueMethod int) RepaColor {
+	var v1, v2 [3]float64
+	v1[0], v1[1], v1[2] = c1.Coordinates(mode)
+	v2[0], v2[1], v2[2] = c2.Coordinates(mode)
+
+	hi := hueIndex(mode)
+	if hi >= 0 {
+		ci := chromaIndex(mode)
+		achromatic1 := math.Abs(v1[ci]) < achromaticThreshold
+		achromatic2 := math.Abs(v2[ci]) < achromaticThreshold
+		if achromatic1 && !achromatic2 {
+			v1[hi] = v2[hi]
+		} else if achromatic2 && !achromatic1 {
+			v2[hi] = v1[hi]
+		}
+		v1[hi], v2[hi] = fixupHues(normalizeHue(v1[hi]), normalizeHue(v2[hi]), hueMethod)
+	}
+
+	a := c1.A + (c2.A-c1.A)*t
+
+	var v [3]float64
+	for i := range v {
+		if i == hi {
+			v[i] = normalizeHue(v1[i] + (v2[i]-v1[i])*t)
+			continue
+		}
+
+		// premultiplied alpha
+		p1 := v1[i] * c1.A
+		p2 := v2[i] * c2.A
+		v[i] = p1 + (p2-p1)*t
+		if a > 0 {
+			v[i] /= a
+		}
+	}
+
+	return CreateColor(mode, v[0], v[1], v[2], a)
+}
+
+type GradientStop struct {
+	Color RepaColor
+	// position of the stop (0-1), negative values are positioned automatically
+	Position float64
+}
+
+type Gradient struct {
+	Stops []GradientStop
+	// interpolation color space (CS_*)
+	Space int
+	// hue interpolation method (HUE_*)
+	Hue int
+	// easing between every pair of stops (EASE_*)
+	Easing int
+}
+
+// Gradient with evenly spaced stops, interpolated in OKLab like CSS gradients
+func NewGradient(colors ...RepaColor) Gradient {
+	stops := make([]GradientStop, len(colors))
+	for i, c := range colors {
+		stops[i] = GradientStop{c, -1}
+	}
+	return Gradient{Stops: stops, Space: CS_OKLAB}
+}
+
+// Parse a gradient stop in the CSS form: `<color> [<percentage>]`
+func ParseGradientStop(s string, usefallback bool) (GradientStop, error) {
+	comps, alpha, err := splitComponents(s)
+	if err != nil {
+		return GradientStop{}, err
+	}
+	if alpha != "" || len(comps) == 0 {
+		return GradientStop{}, errors.New("invalid gradient stop: " + s)
+	}
+
+	stop := GradientStop{Position: -1}
+	if len(comps) > 1 && strings.HasSuffix(comps[len(comps)-1], "%") {
+		stop.Position, err = parseNumber(comps[len(comps)-1], 1)
+		if err != nil {
+			return GradientStop{}, err
+		}
+		comps = comps[:len(comps)-1]
+	}
+
+	stop.Color, err = ParseColor(strings.Join(comps, " "), usefallback)
+	return stop, err
+}
+
+// Stops with resolved positions: missing ones are spread evenly between
+// their neighbours, and positions never decrease
+func (g Gradient) ResolvedStops() []GradientStop {
+	stops := make([]GradientStop, len(g.Stops))
+	copy(stops, g.Stops)
+	if len(stops) == 0 {
+		return stops
+	}
+
+	if stops[0].Position < 0 {
+		stops[0].Position = 0
+	}
+	if stops[len(stops)-1].Position < 0 {
+		stops[len(stops)-1].Position = 1
+	}
+
+	for i := 1; i < len(stops); i++ {
+		if stops[i].Position >= 0 && stops[i].Position < stops[i-1].Position {
+			stops[i].Position = stops[i-1].Position
+		}
+	}
+
+	for i := 1; i < len(stops); i++ {
+		if stops[i].Position >= 0 {
+			continue
+		}
+		j := i
+		for stops[j].Position < 0 {
+			j++
+		}
+		start := stops[i-1].Position
+		step := (stops[j].Position - start) / float64(j-i+1)
+		for k := i; k < j; k++ {
+			stops[k].Position = start + step*float64(k-i+1)
+		}
+	}
+
+	return stops
+}
+
+// Color of the gradient at t (0-1)
+func (g Gradient) At(t float64) RepaColor {
+	stops := g.ResolvedStops()
+	if len(stops) == 0 {
+		return NOCOLOR
+	}
+
+	if t <= stops[0].Position {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if t > stops[i].Position {
+			continue
+		}
+		if t == stops[i].Position {
+			return stops[i].Color
+		}
+
+		span := stops[i].Position - stops[i-1].Position
+		local := Ease(g.Easing, (t-stops[i-1].Position)/span)
+		return Interpolate(stops[i-1].Color, stops[i].Color, local, g.Space, g.Hue)
+	}
+
+	return stops[len(stops)-1].Color
+}
+
+// Sample n evenly spaced colors from the gradient, including both ends
+func (g Gradient) Sample(n int) []RepaColor {
+	colors := make([]RepaColor, n)
+	for i := range colors {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		colors[i] = g.At(t)
+	}
+	return colors
+}
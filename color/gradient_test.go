@@ -0,0 +1,106 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestInterpolateHueMethods(t *testing.T) {
+	c1 := CreateColor(CS_OKLCH, .7, .1, 30, 1)
+	c2 := CreateColor(CS_OKLCH, .7, .1, 90, 1)
+
+	tests := []struct {
+		method int
+		c1, c2 RepaColor
+		hue    float64
+	}{
+		{HUE_SHORTER, c1, c2, 60},
+		{HUE_LONGER, c1, c2, 240},
+		{HUE_INCREASING, c1, c2, 60},
+		{HUE_DECREASING, c1, c2, 240},
+		{HUE_INCREASING, c2, c1, 240},
+		{HUE_DECREASING, c2, c1, 60},
+	}
+
+	for _, test := range tests {
+		_, _, h := Interpolate(test.c1, test.c2, .5, CS_OKLCH, test.method).Coordinates(CS_OKLCH)
+		if !almosteq(h, test.hue) {
+			t.Fatalf("Wrong hue for %s: %v != %v", HueMethodNames[test.method], h, test.hue)
+		}
+	}
+}
+
+func TestInterpolatePowerlessHue(t *testing.T) {
+	red := CreateColor(CS_OKLCH, .6, .2, 30, 1)
+	h := Interpolate(WHITE, red, .5, CS_OKLCH, HUE_SHORTER)
+
+	_, c, hue := h.Coordinates(CS_OKLCH)
+	if !almosteq(hue, 30) || !almosteq(c, .1) {
+		t.Fatalf("Achromatic color should take the hue of the other: %v %v", c, hue)
+	}
+}
+
+func TestInterpolatePremultiplied(t *testing.T) {
+	transparent := RepaColor{Color: BLACK.Color, A: 0}
+	red, _ := ParseColor("#ff0000", false)
+	m := Interpolate(red, transparent, .5, CS_RGB, HUE_SHORTER)
+
+	if !almosteq(m.A, .5) || !almosteq(m.R, 1) || !almosteq(m.G, 0) {
+		t.Fatalf("Transparent color should not darken the mix: %v", m)
+	}
+}
+
+func TestParseGradientStop(t *testing.T) {
+	stop, err := ParseGradientStop("oklch(70% 0.1 200) 30%", false)
+	if err != nil || !almosteq(stop.Position, .3) {
+		t.Fatalf("Wrong stop: %v %v", stop, err)
+	}
+	l, _, _ := stop.Color.OkLch()
+	if !almosteq(l, .7) {
+		t.Fatalf("Wrong stop color: %v", stop.Color)
+	}
+
+	stop, err = ParseGradientStop("red", false)
+	if err != nil || stop.Position >= 0 || stop.Color.Hex() != "#ff0000" {
+		t.Fatalf("Wrong stop: %v %v", stop, err)
+	}
+
+	if _, err = ParseGradientStop("red 30% / 1", false); err == nil {
+		t.Fatalf("Invalid stop should fail")
+	}
+}
+
+func TestGradient(t *testing.T) {
+	red, _ := ParseColor("red", false)
+	yellow, _ := ParseColor("yellow", false)
+	blue, _ := ParseColor("blue", false)
+
+	g := NewGradient(red, yellow, blue)
+	g.Stops[1].Position = .2
+
+	if g.At(0) != red || g.At(.2) != yellow || g.At(1) != blue {
+		t.Fatalf("Gradient should go through its stops")
+	}
+
+	samples := g.Sample(6)
+	if len(samples) != 6 || samples[0] != red || samples[5] != blue {
+		t.Fatalf("Wrong samples: %v", samples)
+	}
+
+	if DeltaEOK(g.At(.1), Interpolate(red, yellow, .5, CS_OKLAB, HUE_SHORTER)) > 1e-6 {
+		t.Fatalf("Wrong color between the stops")
+	}
+}
+
+func TestGradientPositions(t *testing.T) {
+	g := NewGradient(BLACK, GRAY, GRAY, WHITE)
+	g.Stops[2].Position = .8
+	g.Stops[3].Position = .5
+
+	stops := g.ResolvedStops()
+	positions := []float64{0, .4, .8, .8}
+	for i, stop := range stops {
+		if !almosteq(stop.Position, positions[i]) {
+			t.Fatalf("Wrong position of stop %d: %v", i, stop.Position)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package color
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Format used when marshaling colors (see FormatAs). The default is hex, as
+// it is understood by most tools and keeps the alpha channel.
+var MarshalFormat = "hex"
+
+var _ pflag.Value = (*RepaColor)(nil)
+
+func (col RepaColor) MarshalText() ([]byte, error) {
+	s, ok := col.FormatAs(MarshalFormat)
+	if !ok {
+		return nil, fmt.Errorf("unknown color format: %s", MarshalFormat)
+	}
+	return []byte(s), nil
+}
+
+// CSS colors only: no misspelled or non-CSS names and no hash fallback, so
+// typos in configs and flags are reported
+func (col *RepaColor) UnmarshalText(text []byte) error {
+	info, err := ParseColorInfo(string(text), nil, false)
+	if err != nil {
+		return fmt.Errorf("%s: %w", text, err)
+	}
+	if info.Source != SOURCE_CSS {
+		return fmt.Errorf("%s: not a CSS color", text)
+	}
+	*col = info.Color
+	return nil
+}
+
+func (col RepaColor) MarshalJSON() ([]byte, error) {
+	text, err := col.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// JSON strings only, null leaves the color unchanged
+func (col *RepaColor) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return col.UnmarshalText([]byte(s))
+}
+
+// database/sql Scanner, NULL is scanned as NOCOLOR
+func (col *RepaColor) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*col = NOCOLOR
+		return nil
+	case string:
+		return col.UnmarshalText([]byte(v))
+	case []byte:
+		return col.UnmarshalText(v)
+	}
+	return fmt.Errorf("cannot scan %T into a color", src)
+}
+
+// database/sql/driver Valuer, stored as text
+func (col RepaColor) Value() (driver.Value, error) {
+	text, err := col.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// pflag.Value, so colors can be used as command line flags
+func (col *RepaColor) Set(s string) error {
+	return col.UnmarshalText([]byte(s))
+}
+
+func (col *RepaColor) Type() string {
+	return "color"
+}
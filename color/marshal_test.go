@@ -0,0 +1,111 @@
+package color
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	type config struct {
+		Fg RepaColor  `json:"fg"`
+		Bg *RepaColor `json:"bg"`
+	}
+
+	var cfg config
+	if err := json.Unmarshal([]byte(`{"fg": "oklch(70% 0.1 200)", "bg": "rgb(255 0 0 / 50%)"}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Fg.Hex() != "#40b1b7" || cfg.Bg.Hex() != "#ff000080" {
+		t.Fatalf("Wrong unmarshaled colors: %v, %v", cfg.Fg, cfg.Bg)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"fg":"#40b1b7","bg":"#ff000080"}` {
+		t.Fatalf("Wrong JSON: %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"fg": "nosuchcolorxyz"}`), &cfg); err == nil {
+		t.Fatalf("Invalid color should fail")
+	}
+	if err := json.Unmarshal([]byte(`{"fg": 12}`), &cfg); err == nil {
+		t.Fatalf("Non-string color should fail")
+	}
+}
+
+func TestMarshalFormat(t *testing.T) {
+	defer func(f string) { MarshalFormat = f }(MarshalFormat)
+
+	c, _ := ParseColor("#ff8000", false)
+	MarshalFormat = "rgb"
+	text, err := c.MarshalText()
+	if err != nil || string(text) != "rgb(255 128 0)" {
+		t.Fatalf("Wrong text: %s, %v", text, err)
+	}
+
+	var c2 RepaColor
+	if err := c2.UnmarshalText(text); err != nil || c2.Hex() != "#ff8000" {
+		t.Fatalf("Wrong round trip: %v, %v", c2, err)
+	}
+
+	MarshalFormat = "nosuchformat"
+	if _, err := c.MarshalText(); err == nil {
+		t.Fatalf("Unknown format should fail")
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	var c RepaColor
+	for _, src := range []any{"#336699", []byte("#336699")} {
+		if err := c.Scan(src); err != nil || c.Hex() != "#336699" {
+			t.Fatalf("Wrong scanned color from %T: %v, %v", src, c, err)
+		}
+	}
+
+	v, err := c.Value()
+	if err != nil || v != "#336699" {
+		t.Fatalf("Wrong value: %v, %v", v, err)
+	}
+
+	if err := c.Scan(nil); err != nil || c != NOCOLOR {
+		t.Fatalf("NULL should be scanned as NOCOLOR: %v, %v", c, err)
+	}
+	if err := c.Scan(42); err == nil {
+		t.Fatalf("Scanning a number should fail")
+	}
+}
+
+func TestPflagValue(t *testing.T) {
+	c := BLACK
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Var(&c, "color", "Color")
+
+	if err := fs.Parse([]string{"--color", "rebeccapurple"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hex() != "#663399" {
+		t.Fatalf("Wrong flag value: %v", c)
+	}
+	if f := fs.Lookup("color"); f.Value.Type() != "color" || f.DefValue != "#000000" {
+		t.Fatalf("Wrong flag: %s, %s", f.Value.Type(), f.DefValue)
+	}
+
+	for _, invalid := range []string{"#12", "bold", "reed", "burnt sienna"} {
+		if err := fs.Parse([]string{"--color", invalid}); err == nil {
+			t.Fatalf("Invalid color should fail: %s", invalid)
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	for _, input := range []string{"bold", "reed", "dark sea gren", "pale teal"} {
+		c := BLACK
+		if err := c.UnmarshalText([]byte(input)); err == nil || c != BLACK {
+			t.Errorf("%s should be rejected, got %s", input, c.Hex())
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Mix two colors following the CSS color-mix() rules. The percentages are
+// given as 0-1 fractions, negative values mean an omitted percentage. If the
+// percentages add up to less than 100% the alpha of the result is reduced.
+func Mix(c1, c2 RepaColor, p1, p2 float64, mode int, hueMethod int) (RepaColor, error) {
+	if p1 > 1 || p2 > 1 {
+		return NOCOLOR, errors.New("mix percentages must be between 0% and 100%")
+	}
+
+	switch {
+	case p1 < 0 && p2 < 0:
+		p1, p2 = .5, .5
+	case p1 < 0:
+		p1 = 1 - p2
+	case p2 < 0:
+		p2 = 1 - p1
+	}
+
+	sum := p1 + p2
+	if sum <= 0 {
+		return NOCOLOR, errors.New("mix percentages add up to 0%")
+	}
+
+	multiplier := 1.0
+	if sum < 1 {
+		multiplier = sum
+	}
+
+	col := Interpolate(c1, c2, p2/sum, mode, hueMethod)
+	col.A *= multiplier
+
+	return col, nil
+}
+
+// Split function arguments at the top level commas
+func splitArguments(args string) ([]string, error) {
+	var parts []string
+	depth := 0
+	start := 0
+
+	for i, r := range args {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(args[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+
+	return append(parts, strings.TrimSpace(args[start:])), nil
+}
+
+// Parse a color-mix() color with its optional percentage (before or after the
+// color), the percentage is negative if omitted
+func parseMixColor(s string) (RepaColor, float64, error) {
+	comps, alpha, err := splitComponents(s)
+	if err != nil {
+		return NOCOLOR, 0, err
+	}
+	if alpha != "" || len(comps) == 0 {
+		return NOCOLOR, 0, fmt.Errorf("color-mix(): invalid color: %s", s)
+	}
+
+	p := -1.0
+	if len(comps) > 1 {
+		if strings.HasSuffix(comps[0], "%") {
+			p, err = parseNumber(comps[0], 1)
+			comps = comps[1:]
+		} else if strings.HasSuffix(comps[len(comps)-1], "%") {
+			p, err = parseNumber(comps[len(comps)-1], 1)
+			comps = comps[:len(comps)-1]
+		}
+		if err != nil {
+			return NOCOLOR, 0, fmt.Errorf("color-mix(): invalid percentage: %v", err)
+		}
+		if p < 0 {
+			return NOCOLOR, 0, errors.New("mix percentages must be between 0% and 100%")
+		}
+	}
+
+	col, err := parseColor(strings.Join(comps, " "))
+	return col, p, err
+}
+
+// color-mix([in <space> [<hue method> hue]], <color> [<percentage>], <color> [<percentage>])
+func parseColorMixFunction(args string) (RepaColor, error) {
+	parts, err := splitArguments(args)
+	if err != nil {
+		return NOCOLOR, err
+	}
+
+	mode, hueMethod := CS_OKLAB, HUE_SHORTER
+	if len(parts) == 3 {
+		comps := strings.Fields(strings.ToLower(parts[0]))
+		if len(comps) < 2 || comps[0] != "in" {
+			return NOCOLOR, fmt.Errorf("color-mix(): invalid interpolation method: %s", parts[0])
+		}
+
+		var ok bool
+		mode, ok = ParseColorSpace(comps[1])
+		if !ok {
+			return NOCOLOR, fmt.Errorf("color-mix(): unsupported color space: %s", comps[1])
+		}
+
+		switch {
+		case len(comps) == 2:
+		case len(comps) == 4 && comps[3] == "hue" && hueIndex(mode) >= 0:
+			hueMethod, ok = ParseHueMethod(comps[2])
+			if !ok {
+				return NOCOLOR, fmt.Errorf("color-mix(): unknown hue interpolation method: %s", comps[2])
+			}
+		default:
+			return NOCOLOR, fmt.Errorf("color-mix(): invalid interpolation method: %s", parts[0])
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return NOCOLOR, errors.New("color-mix(): expected two colors")
+	}
+
+	c1, p1, err := parseMixColor(parts[0])
+	if err != nil {
+		return NOCOLOR, err
+	}
+	c2, p2, err := parseMixColor(parts[1])
+	if err != nil {
+		return NOCOLOR, err
+	}
+
+	return Mix(c1, c2, p1, p2, mode, hueMethod)
+}
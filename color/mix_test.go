@@ -0,0 +1,72 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestMix(t *testing.T) {
+	red, _ := ParseColor("red", false)
+	blue, _ := ParseColor("blue", false)
+
+	tests := []struct {
+		p1, p2 float64
+		t, a   float64
+	}{
+		{-1, -1, .5, 1},
+		{.3, -1, .7, 1},
+		{-1, .3, .3, 1},
+		{.6, .6, .5, 1},
+		{.2, .3, .6, .5},
+	}
+
+	for _, test := range tests {
+		m, err := Mix(red, blue, test.p1, test.p2, CS_OKLCH, HUE_SHORTER)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Interpolate(red, blue, test.t, CS_OKLCH, HUE_SHORTER)
+		if DeltaEOK(m, expected) > 1e-6 || !almosteq(m.A, test.a) {
+			t.Fatalf("Wrong mix for %v %v: %v", test.p1, test.p2, m)
+		}
+	}
+
+	if _, err := Mix(red, blue, 0, 0, CS_OKLAB, HUE_SHORTER); err == nil {
+		t.Fatalf("Mix with 0%% should fail")
+	}
+}
+
+func TestParseColorMix(t *testing.T) {
+	red, _ := ParseColor("red", false)
+	blue, _ := ParseColor("blue", false)
+
+	tests := []struct {
+		cstr     string
+		expected RepaColor
+	}{
+		{"color-mix(in srgb, red, blue)", Interpolate(red, blue, .5, CS_RGB, HUE_SHORTER)},
+		{"color-mix(red 25%, blue)", Interpolate(red, blue, .75, CS_OKLAB, HUE_SHORTER)},
+		{"color-mix(in oklch longer hue, 30% red, blue)", Interpolate(red, blue, .7, CS_OKLCH, HUE_LONGER)},
+		{"color-mix(in lch, rgb(255, 0, 0) 50%, color-mix(in srgb, blue, blue))", Interpolate(red, blue, .5, CS_LCH, HUE_SHORTER)},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.cstr, false)
+		if err != nil {
+			t.Fatalf("%s: %v", test.cstr, err)
+		}
+		if DeltaEOK(c, test.expected) > 1e-6 {
+			t.Fatalf("Wrong color for %s: %v", test.cstr, c.Hex())
+		}
+	}
+
+	for _, cstr := range []string{
+		"color-mix(in foo, red, blue)",
+		"color-mix(in srgb longer hue, red, blue)",
+		"color-mix(in srgb, red)",
+		"color-mix(in srgb, red 120%, blue)",
+	} {
+		if _, err := ParseColor(cstr, false); err == nil {
+			t.Fatalf("%s should fail", cstr)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package color
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Named color dictionaries, bundled as `name<TAB>#rrggbb` lines
+
+//go:embed names/*.txt
+var nameFiles embed.FS
+
+var NameDictionaries = []string{"css", "x11", "xkcd", "crayola"}
+
+// Color distance metrics for the name lookup
+const (
+	DISTANCE_CIEDE2000 = iota
+	DISTANCE_OK        = iota
+)
+
+var DistanceNames = []string{"ciede2000", "deltaeok"}
+
+type NamedColor struct {
+	Name  string
+	Color RepaColor
+}
+
+type NameMatch struct {
+	NamedColor
+	Dictionary string
+	// CIEDE2000 in the usual 0-100 scale, or deltaEOK
+	Distance float64
+}
+
+var dictionaries = map[string][]NamedColor{}
+var dictionariesLock sync.Mutex
+
+func ParseDistance(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "ciede2000", "de2000", "2000":
+		return DISTANCE_CIEDE2000, true
+	case "deltaeok", "ok", "oklab":
+		return DISTANCE_OK, true
+	}
+	return 0, false
+}
+
+// Colors of the named dictionary, loaded on first use
+func GetDictionary(name string) ([]NamedColor, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	dictionariesLock.Lock()
+	defer dictionariesLock.Unlock()
+
+	if dict, ok := dictionaries[name]; ok {
+		return dict, nil
+	}
+
+	f, err := nameFiles.Open("names/" + name + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("unknown color dictionary: %s", name)
+	}
+	defer f.Close()
+
+	var dict []NamedColor
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cname, hex, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid line: %s", name, line)
+		}
+		col, err := parseColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid color: %s", name, hex)
+		}
+		dict = append(dict, NamedColor{cname, col})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	dictionaries[name] = dict
+	return dict, nil
+}
+
+func colorDistance(c1, c2 RepaColor, distance int) float64 {
+	if distance == DISTANCE_OK {
+		return DeltaEOK(c1, c2)
+	}
+	return c1.ToGamut().DistanceCIEDE2000(c2.ToGamut().Color) * 100
+}
+
+// The `n` closest named colors from the given dictionaries, closest first
+func NearestNames(col RepaColor, dicts []string, n int, distance int) ([]NameMatch, error) {
+	var matches []NameMatch
+	for _, d := range dicts {
+		dict, err := GetDictionary(d)
+		if err != nil {
+			return nil, err
+		}
+		for _, nc := range dict {
+			matches = append(matches, NameMatch{nc, strings.ToLower(d), colorDistance(col, nc.Color, distance)})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	if n > 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches, nil
+}
+
+// The closest CSS color name by CIEDE2000
+func NearestName(col RepaColor) (NameMatch, bool) {
+	matches, err := NearestNames(col, []string{"css"}, 1, DISTANCE_CIEDE2000)
+	if err != nil || len(matches) == 0 {
+		return NameMatch{}, false
+	}
+	return matches[0], true
+}
@@ -0,0 +1,48 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestGetDictionary(t *testing.T) {
+	for _, name := range NameDictionaries {
+		dict, err := GetDictionary(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dict) < 100 {
+			t.Fatalf("Too few colors in %s: %d", name, len(dict))
+		}
+	}
+
+	if _, err := GetDictionary("nosuchdict"); err == nil {
+		t.Fatalf("Unknown dictionary should fail")
+	}
+}
+
+func TestNearestNames(t *testing.T) {
+	c, _ := ParseColor("#ff0001", false)
+	match, ok := NearestName(c)
+	if !ok || match.Name != "red" || match.Distance > 1 {
+		t.Fatalf("Wrong nearest name: %v", match)
+	}
+
+	c, _ = ParseColor("#7e1e9c", false)
+	for _, distance := range []int{DISTANCE_CIEDE2000, DISTANCE_OK} {
+		matches, err := NearestNames(c, NameDictionaries, 5, distance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 5 {
+			t.Fatalf("Wrong number of matches: %d", len(matches))
+		}
+		if matches[0].Name != "purple" || matches[0].Dictionary != "xkcd" || !almosteq(matches[0].Distance, 0) {
+			t.Fatalf("Wrong nearest %s match: %v", DistanceNames[distance], matches[0])
+		}
+		for i := 1; i < len(matches); i++ {
+			if matches[i].Distance < matches[i-1].Distance {
+				t.Fatalf("Matches should be sorted by distance")
+			}
+		}
+	}
+}
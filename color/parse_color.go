@@ -9,18 +9,34 @@ import (
 )
 
 func ParseColor(cstr string, usefallback bool) (col RepaColor, err error) {
-	col = NOCOLOR
-	c, err := csscolorparser.Parse(cstr)
+	return ParseColorVars(cstr, nil, usefallback)
+}
+
+// Parse a color resolving the var(--name) references with the given variables
+// (the keys can be given with or without the leading "--")
+func ParseColorVars(cstr string, vars map[string]string, usefallback bool) (RepaColor, error) {
+	info, err := ParseColorInfo(cstr, vars, usefallback)
+	return info.Color, err
+}
+
+// Parse a color and report how it was interpreted: as a CSS color, as a
+// (possibly misspelled or modified) color name, or with the hash fallback.
+// Without usefallback only CSS colors are accepted.
+func ParseColorInfo(cstr string, vars map[string]string, usefallback bool) (info ColorInfo, err error) {
+	info.Color = NOCOLOR
+	c, err := parseColorVars(cstr, vars)
 
 	if err == nil {
-		col = MakeColor(c)
+		info.Color = c
 	} else if (usefallback) {
+		// misspelled names are accepted only where the hash fallback would be
+		if match, ok := matchColorName(cstr); ok {
+			return match, nil
+		}
 		hash := md5.Sum([]byte(cstr))
 		strhash := hex.EncodeToString(hash[:])
-		c, err = csscolorparser.Parse("#" + strhash[:6])
-		if err == nil {
-			col = MakeColor(c)
-		}
+		info.Color, err = parseColor("#" + strhash[:6])
+		info.Source = SOURCE_FALLBACK
 	} else {
 		err = errors.New("cannot parse color")
 	}
@@ -28,6 +44,27 @@ func ParseColor(cstr string, usefallback bool) (col RepaColor, err error) {
 	return
 }
 
+func parseColorVars(cstr string, vars map[string]string) (RepaColor, error) {
+	s, err := substituteVars(cstr, vars)
+	if err != nil {
+		return NOCOLOR, err
+	}
+	return parseColor(s)
+}
+
+func parseColor(cstr string) (RepaColor, error) {
+	if col, ok, err := parseColorFunction(cstr); ok {
+		return col, err
+	}
+
+	c, err := csscolorparser.Parse(cstr)
+	if err != nil {
+		return NOCOLOR, err
+	}
+
+	return MakeColor(c), nil
+}
+
 func GetName(col RepaColor) (string, bool) {
 	return csscolorparser.Color{R: col.R, G: col.G, B: col.B, A: col.A}.Name()
 }
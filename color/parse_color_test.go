@@ -121,3 +121,86 @@ func TestParseRgbaColor(t *testing.T) {
 }
 
 // switched to mazznoer/csscolorparser, so this test is no longer needed, but I keep it none the less
+
+func TestParseColorFunction(t *testing.T) {
+	tests := []struct {
+		input string
+		hex   string
+	}{
+		{"color(srgb 1 0.5 0)", "#ff8000"},
+		{"color(srgb 100% 50% 0% / 50%)", "#ff800080"},
+		{"color(display-p3 0.9175 0.2003 0.1386)", "#ff0000"},
+		{"color(xyz-d65 0.9505 1 1.089)", "#ffffff"},
+		{"color(rec2020 0 0 0)", "#000000"},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.input, false)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", test.input, err)
+		}
+		if c.Hex() != test.hex {
+			t.Fatalf("Wrong color parsed: %s => %s (vs. %s)", test.input, c.Hex(), test.hex)
+		}
+	}
+
+	for _, input := range []string{"color(foo 1 0 0)", "color(srgb 1 0)", "color(srgb a b c)"} {
+		if _, err := ParseColor(input, false); err == nil {
+			t.Fatalf("Invalid color parsed: %s", input)
+		}
+	}
+}
+
+func TestParseLabFunctions(t *testing.T) {
+	tests := []struct {
+		input string
+		hex   string
+	}{
+		{"oklch(62.8% 0.2577 29.23)", "#ff0000"},
+		{"oklch(0.628 0.2577 29.23 / 0.5)", "#ff000080"},
+		{"oklab(0.628 0.2249 0.1258)", "#ff0000"},
+		{"oklch(100% 0 0)", "#ffffff"},
+		{"oklch(0% 0 none)", "#000000"},
+		{"lch(50% 0 0)", "#777777"},
+		{"lab(100% 0 0)", "#ffffff"},
+		{"oklch(62.8% 0.2577 0.0812turn)", "#ff0000"},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.input, false)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", test.input, err)
+		}
+		if c.Hex() != test.hex {
+			t.Fatalf("Wrong color parsed: %s => %s (vs. %s)", test.input, c.Hex(), test.hex)
+		}
+	}
+}
+
+func TestParsePreservesSourceSpace(t *testing.T) {
+	tests := []struct {
+		input string
+		repr  func(RepaColor) string
+	}{
+		{"oklch(70% 0.25 150)", RepaColor.OkLchString},
+		{"oklch(70% 0.25 150 / 0.5)", RepaColor.OkLchString},
+		{"oklab(50% -0.3 0.2)", RepaColor.OkLabString},
+		{"lab(50% 80 -120)", RepaColor.LabString},
+		{"lch(90% 140 200)", RepaColor.LchString},
+		{"color(display-p3 1 0 0)", RepaColor.DisplayP3String},
+		{"color(rec2020 0 1 0)", RepaColor.Rec2020String},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.input, false)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", test.input, err)
+		}
+		if c.InGamut() {
+			t.Fatalf("Color should be out of gamut: %s", test.input)
+		}
+		if test.repr(c) != test.input {
+			t.Fatalf("Color does not round trip: %s => %s", test.input, test.repr(c))
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parsing of the CSS color functions that are not handled by csscolorparser
+
+var colorFunctionSpaces = map[string]int{
+	"srgb":         CS_RGB,
+	"srgb-linear":  CS_LINEARRGB,
+	"display-p3":   CS_DISPLAYP3,
+	"rec2020":      CS_REC2020,
+	"a98-rgb":      CS_A98RGB,
+	"prophoto-rgb": CS_PROPHOTO,
+	"xyz":          CS_XYZ,
+	"xyz-d65":      CS_XYZ,
+}
+
+// Split `name(args)` into its name and arguments
+func splitFunction(s string) (name, args string, ok bool) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexByte(s, '(')
+	if i <= 0 || !strings.HasSuffix(s, ")") {
+		return "", "", false
+	}
+	return strings.ToLower(strings.TrimSpace(s[:i])), s[i+1 : len(s)-1], true
+}
+
+// Split space separated function arguments, the optional alpha value is
+// separated with a "/". Nested functions are kept as a single component.
+func splitComponents(args string) (comps []string, alpha string, err error) {
+	var sb strings.Builder
+	depth := 0
+	inAlpha := false
+
+	flush := func() {
+		if sb.Len() == 0 {
+			return
+		}
+		if inAlpha {
+			alpha += sb.String()
+		} else {
+			comps = append(comps, sb.String())
+		}
+		sb.Reset()
+	}
+
+	for _, r := range args {
+		switch {
+		case r == '(':
+			depth++
+			sb.WriteRune(r)
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, "", errors.New("unbalanced parentheses")
+			}
+			sb.WriteRune(r)
+		case depth > 0:
+			sb.WriteRune(r)
+		case r == '/':
+			flush()
+			if inAlpha {
+				return nil, "", errors.New("multiple alpha separators")
+			}
+			inAlpha = true
+		case r == ' ' || r == '\t' || r == '\n' || r == ',':
+			flush()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	flush()
+
+	if depth != 0 {
+		return nil, "", errors.New("unbalanced parentheses")
+	}
+
+	return
+}
+
+// Parse a number or percentage, 100% equals to `percentRef`
+func parseNumber(s string, percentRef float64) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(s[:len(s)-1], 64)
+		if err != nil {
+			return 0, err
+		}
+		return v / 100 * percentRef, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Parse a hue, number or angle (deg, rad, grad, turn), in degrees
+func parseHue(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"deg", 1},
+		{"grad", 360.0 / 400.0},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			v, err := strconv.ParseFloat(s[:len(s)-len(u.suffix)], 64)
+			return v * u.scale, err
+		}
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseAlpha(s string) (float64, error) {
+	if s == "" {
+		return 1, nil
+	}
+	a, err := parseNumber(s, 1)
+	if err != nil {
+		return 0, err
+	}
+	return clamp01(a), nil
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Try to parse the supported color functions, `ok` is false if the input is
+// not one of them.
+func parseColorFunction(cstr string) (col RepaColor, ok bool, err error) {
+	name, args, isfn := splitFunction(cstr)
+	if !isfn {
+		return NOCOLOR, false, nil
+	}
+
+	if isRelative(args) {
+		col, err = parseRelativeColor(name, args)
+		return col, true, err
+	}
+
+	switch name {
+	case "color":
+		col, err = parseColorSpaceFunction(args)
+		return col, true, err
+	case "lab", "lch", "oklab", "oklch":
+		col, err = parseLabFunction(name, args)
+		return col, true, err
+	case "color-mix":
+		col, err = parseColorMixFunction(args)
+		return col, true, err
+	}
+
+	return NOCOLOR, false, nil
+}
+
+// color(<space> c1 c2 c3 [/ alpha])
+func parseColorSpaceFunction(args string) (RepaColor, error) {
+	comps, alphastr, err := splitComponents(args)
+	if err != nil {
+		return NOCOLOR, err
+	}
+	if len(comps) != 4 {
+		return NOCOLOR, fmt.Errorf("color(): expected a color space and 3 components, got %d values", len(comps))
+	}
+
+	mode, ok := colorFunctionSpaces[strings.ToLower(comps[0])]
+	if !ok {
+		return NOCOLOR, fmt.Errorf("color(): unsupported color space: %s", comps[0])
+	}
+
+	var v [3]float64
+	for i := 0; i < 3; i++ {
+		v[i], err = parseNumber(comps[i+1], 1)
+		if err != nil {
+			return NOCOLOR, fmt.Errorf("color(): invalid component: %s", comps[i+1])
+		}
+	}
+
+	a, err := parseAlpha(alphastr)
+	if err != nil {
+		return NOCOLOR, fmt.Errorf("color(): invalid alpha: %s", alphastr)
+	}
+
+	return CreateColor(mode, v[0], v[1], v[2], a), nil
+}
+
+// lab(), lch(), oklab() and oklch() with the CSS Color 4 reference ranges
+// for percentages
+func parseLabFunction(name, args string) (RepaColor, error) {
+	comps, alphastr, err := splitComponents(args)
+	if err != nil {
+		return NOCOLOR, err
+	}
+	if len(comps) != 3 {
+		return NOCOLOR, fmt.Errorf("%s(): expected 3 components, got %d", name, len(comps))
+	}
+
+	var v [3]float64
+	var mode int
+	switch name {
+	case "lab":
+		mode = CS_LAB
+		v[0], err = parseNumber(comps[0], 100)
+		if err == nil {
+			v[1], err = parseNumber(comps[1], 125)
+		}
+		if err == nil {
+			v[2], err = parseNumber(comps[2], 125)
+		}
+		// colorful uses 0..1 lightness and scaled a/b values
+		v[0], v[1], v[2] = v[0]/100, v[1]/100, v[2]/100
+	case "lch":
+		mode = CS_LCH
+		v[0], err = parseNumber(comps[0], 100)
+		if err == nil {
+			v[1], err = parseNumber(comps[1], 150)
+		}
+		if err == nil {
+			v[2], err = parseHue(comps[2])
+		}
+		v[0], v[1] = v[0]/100, v[1]/100
+	case "oklab":
+		mode = CS_OKLAB
+		v[0], err = parseNumber(comps[0], 1)
+		if err == nil {
+			v[1], err = parseNumber(comps[1], 0.4)
+		}
+		if err == nil {
+			v[2], err = parseNumber(comps[2], 0.4)
+		}
+	case "oklch":
+		mode = CS_OKLCH
+		v[0], err = parseNumber(comps[0], 1)
+		if err == nil {
+			v[1], err = parseNumber(comps[1], 0.4)
+		}
+		if err == nil {
+			v[2], err = parseHue(comps[2])
+		}
+	}
+	if err != nil {
+		return NOCOLOR, fmt.Errorf("%s(): invalid component: %v", name, err)
+	}
+
+	a, err := parseAlpha(alphastr)
+	if err != nil {
+		return NOCOLOR, fmt.Errorf("%s(): invalid alpha: %s", name, alphastr)
+	}
+
+	return CreateColor(mode, v[0], v[1], v[2], a), nil
+}
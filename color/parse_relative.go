@@ -0,0 +1,247 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// CSS Color 5 relative color syntax: `<fn>(from <color> c1 c2 c3 [/ alpha])`
+
+// not a real color space, only used by hwb()
+const csHWB = -1
+
+type relativeSpace struct {
+	mode     int
+	channels [3]string
+	// 100% in CSS units, ignored for hues
+	refs [3]float64
+	// CSS units per coordinate unit
+	scales [3]float64
+	// index of the hue channel, -1 if none
+	hue int
+}
+
+var relativeSpaces = map[string]relativeSpace{
+	"rgb":   {CS_RGB, [3]string{"r", "g", "b"}, [3]float64{255, 255, 255}, [3]float64{255, 255, 255}, -1},
+	"hsl":   {CS_HSL, [3]string{"h", "s", "l"}, [3]float64{0, 100, 100}, [3]float64{1, 100, 100}, 0},
+	"hwb":   {csHWB, [3]string{"h", "w", "b"}, [3]float64{0, 100, 100}, [3]float64{1, 100, 100}, 0},
+	"lab":   {CS_LAB, [3]string{"l", "a", "b"}, [3]float64{100, 125, 125}, [3]float64{100, 100, 100}, -1},
+	"lch":   {CS_LCH, [3]string{"l", "c", "h"}, [3]float64{100, 150, 0}, [3]float64{100, 100, 1}, 2},
+	"oklab": {CS_OKLAB, [3]string{"l", "a", "b"}, [3]float64{1, .4, .4}, [3]float64{1, 1, 1}, -1},
+	"oklch": {CS_OKLCH, [3]string{"l", "c", "h"}, [3]float64{1, .4, 0}, [3]float64{1, 1, 1}, 2},
+}
+
+// Space of color(from <color> <space> ...)
+func colorFunctionRelativeSpace(name string) (relativeSpace, bool) {
+	mode, ok := colorFunctionSpaces[strings.ToLower(name)]
+	if !ok {
+		return relativeSpace{}, false
+	}
+
+	channels := [3]string{"r", "g", "b"}
+	if mode == CS_XYZ {
+		channels = [3]string{"x", "y", "z"}
+	}
+	return relativeSpace{mode, channels, [3]float64{1, 1, 1}, [3]float64{1, 1, 1}, -1}, true
+}
+
+// Coordinates of the color in the CSS units of the space
+func (s relativeSpace) coordinates(col RepaColor) (v [3]float64) {
+	if s.mode == csHWB {
+		h, sat, val := col.Hsv()
+		v = [3]float64{h, (1 - sat) * val, 1 - val}
+	} else {
+		v[0], v[1], v[2] = col.Coordinates(s.mode)
+	}
+
+	for i := range v {
+		v[i] *= s.scales[i]
+	}
+	return
+}
+
+// Create a color from CSS unit values
+func (s relativeSpace) create(v [3]float64, a float64) RepaColor {
+	for i := range v {
+		v[i] /= s.scales[i]
+	}
+
+	if s.mode == csHWB {
+		w, b := v[1], v[2]
+		if w+b >= 1 {
+			gray := w / (w + b)
+			return RepaColor{Color: colorful.Color{R: gray, G: gray, B: gray}, A: a}
+		}
+		return RepaColor{Color: colorful.Hsv(normalizeHue(v[0]), 1-w/(1-b), 1-b), A: a}
+	}
+
+	return CreateColor(s.mode, v[0], v[1], v[2], a)
+}
+
+// Check if the arguments of a color function use the relative syntax
+func isRelative(args string) bool {
+	fields := strings.Fields(strings.ToLower(args))
+	return len(fields) > 0 && fields[0] == "from"
+}
+
+// Parse `from <color> [<space>] c1 c2 c3 [/ alpha]` for the given function
+func parseRelativeColor(name, args string) (RepaColor, error) {
+	comps, alphastr, err := splitComponents(args)
+	if err != nil {
+		return NOCOLOR, err
+	}
+	if len(comps) < 2 || strings.ToLower(comps[0]) != "from" {
+		return NOCOLOR, fmt.Errorf("%s(): invalid relative color", name)
+	}
+
+	origin, err := parseColor(comps[1])
+	if err != nil {
+		return NOCOLOR, fmt.Errorf("%s(): invalid origin color %s: %v", name, comps[1], err)
+	}
+	comps = comps[2:]
+
+	var space relativeSpace
+	var ok bool
+	switch name {
+	case "color":
+		if len(comps) == 0 {
+			return NOCOLOR, errors.New("color(): missing color space")
+		}
+		space, ok = colorFunctionRelativeSpace(comps[0])
+		if !ok {
+			return NOCOLOR, fmt.Errorf("color(): unsupported color space: %s", comps[0])
+		}
+		comps = comps[1:]
+	case "rgba", "hsla":
+		space, ok = relativeSpaces[name[:3]]
+	default:
+		space, ok = relativeSpaces[name]
+	}
+	if !ok {
+		return NOCOLOR, fmt.Errorf("%s(): relative colors are not supported", name)
+	}
+	if len(comps) != 3 {
+		return NOCOLOR, fmt.Errorf("%s(): expected 3 components, got %d", name, len(comps))
+	}
+
+	// channel keywords
+	ov := space.coordinates(origin)
+	vals := map[string]float64{"alpha": origin.A}
+	for i, ch := range space.channels {
+		vals[ch] = ov[i]
+	}
+
+	var v [3]float64
+	for i, comp := range comps {
+		v[i], err = evalComponent(comp, vals, space.refs[i], i == space.hue)
+		if err != nil {
+			return NOCOLOR, fmt.Errorf("%s(): invalid component %s: %v", name, comp, err)
+		}
+	}
+
+	a := origin.A
+	if alphastr != "" {
+		a, err = evalComponent(alphastr, vals, 1, false)
+		if err != nil {
+			return NOCOLOR, fmt.Errorf("%s(): invalid alpha %s: %v", name, alphastr, err)
+		}
+	}
+
+	return space.create(v, clamp01(a)), nil
+}
+
+// Value of a relative color component: keyword, number, percentage, hue or calc()
+func evalComponent(comp string, vals map[string]float64, ref float64, hue bool) (float64, error) {
+	s := strings.ToLower(strings.TrimSpace(comp))
+
+	if v, ok := vals[s]; ok {
+		return v, nil
+	}
+	if name, args, ok := splitFunction(s); ok {
+		if name != "calc" {
+			return 0, fmt.Errorf("unsupported function: %s()", name)
+		}
+		return evalCalc(args, vals, ref, hue)
+	}
+	if hue {
+		return parseHue(s)
+	}
+	return parseNumber(s, ref)
+}
+
+// Replace the var(--name[, fallback]) references with their values
+func substituteVars(s string, vars map[string]string) (string, error) {
+	// the number of substitutions is limited to catch circular references
+	for n := 0; n < 100; n++ {
+		start := findVar(s)
+		if start < 0 {
+			return s, nil
+		}
+
+		end, depth := -1, 0
+		for i := start + 3; i < len(s); i++ {
+			if s[i] == '(' {
+				depth++
+			} else if s[i] == ')' {
+				depth--
+				if depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			return "", errors.New("var(): unbalanced parentheses")
+		}
+
+		name, fallback, hasFallback := strings.Cut(s[start+4:end], ",")
+		name = strings.TrimSpace(name)
+		value, ok := lookupVar(vars, name)
+		if !ok {
+			if !hasFallback {
+				return "", fmt.Errorf("undefined variable: %s", name)
+			}
+			value = strings.TrimSpace(fallback)
+		}
+
+		s = s[:start] + value + s[end+1:]
+	}
+
+	return "", errors.New("var(): too many substitutions, circular reference?")
+}
+
+// Index of the first `var(` that is not part of another name
+func findVar(s string) int {
+	lower := strings.ToLower(s)
+	offset := 0
+	for {
+		i := strings.Index(lower[offset:], "var(")
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		if i == 0 || !isNameChar(lower[i-1]) {
+			return i
+		}
+		offset = i + 4
+	}
+}
+
+func isNameChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// Variables can be given with or without the leading "--"
+func lookupVar(vars map[string]string, name string) (string, bool) {
+	if v, ok := vars[name]; ok {
+		return v, true
+	}
+	if v, ok := vars[strings.TrimPrefix(name, "--")]; ok {
+		return v, true
+	}
+	v, ok := vars["--"+name]
+	return v, ok
+}
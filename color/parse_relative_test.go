@@ -0,0 +1,114 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestParseRelativeColor(t *testing.T) {
+	tests := []struct {
+		cstr     string
+		expected string
+	}{
+		{"rgb(from #ff8000 r g b)", "#ff8000"},
+		{"rgb(from #ff8000 b g r)", "#0080ff"},
+		{"rgb(from red r calc(g + 51) b / 0.5)", "#ff330080"},
+		{"hsl(from red calc(h + 120) s l)", "#00ff00"},
+		{"hsl(from #ff0000 h s calc(l / 2))", "#800000"},
+		{"hwb(from red h 100% b)", "#ffffff"},
+		{"oklch(from #3366cc l c calc(h + 0deg))", "#3366cc"},
+		{"lch(from #3366cc l c h)", "#3366cc"},
+		{"lab(from white l 0 0)", "#ffffff"},
+		{"color(from red srgb b g r)", "#0000ff"},
+		{"oklab(from rgb(from blue b g r) l a b)", "#ff0000"},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.cstr, false)
+		if err != nil {
+			t.Fatalf("%s: %v", test.cstr, err)
+		}
+		if c.Hex() != test.expected {
+			t.Fatalf("Wrong color for %s: %s != %s", test.cstr, c.Hex(), test.expected)
+		}
+	}
+
+	c, _ := ParseColor("rgb(from red r g b / calc(alpha / 4))", false)
+	if !almosteq(c.A, .25) {
+		t.Fatalf("Wrong alpha: %v", c.A)
+	}
+
+	c, _ = ParseColor("oklch(from #3366cc calc(l + 0.1) c h)", false)
+	base, _ := ParseColor("#3366cc", false)
+	bl, bc, bh := base.OkLch()
+	l, ch, h := c.Coordinates(CS_OKLCH)
+	if !almosteq(l, bl+.1) || !almosteq(ch, bc) || !almosteq(h, bh) {
+		t.Fatalf("Wrong relative oklch: %v %v %v", l, ch, h)
+	}
+
+	for _, cstr := range []string{
+		"rgb(from red r g)",
+		"rgb(from red r g x)",
+		"rgb(from red r calc(g / 0) b)",
+		"rgb(from red r calc(g + ) b)",
+		"oklch(from nosuchcolor l c h)",
+	} {
+		if _, err := ParseColor(cstr, false); err == nil {
+			t.Fatalf("%s should fail", cstr)
+		}
+	}
+}
+
+func TestEvalCalc(t *testing.T) {
+	vals := map[string]float64{"l": .5, "h": 90}
+
+	tests := []struct {
+		expr     string
+		ref      float64
+		hue      bool
+		expected float64
+	}{
+		{"1 + 2 * 3", 1, false, 7},
+		{"(1 + 2) * 3", 1, false, 9},
+		{"l * 2 - -1", 1, false, 2},
+		{"calc(l + 10%)", 1, false, .6},
+		{"50% / 2", 100, false, 25},
+		{"h + 0.5turn", 0, true, 270},
+	}
+
+	for _, test := range tests {
+		v, err := evalCalc(test.expr, vals, test.ref, test.hue)
+		if err != nil || !almosteq(v, test.expected) {
+			t.Fatalf("Wrong value for %s: %v %v", test.expr, v, err)
+		}
+	}
+}
+
+func TestParseColorVars(t *testing.T) {
+	vars := map[string]string{
+		"--brand":  "#3366cc",
+		"accent":   "var(--brand)",
+		"--step":   "0.1",
+		"--loop-a": "var(--loop-b)",
+		"--loop-b": "var(--loop-a)",
+	}
+
+	c, err := ParseColorVars("oklch(from var(--accent) calc(l + var(--step)) c h)", vars, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := ParseColor("oklch(from #3366cc calc(l + 0.1) c h)", false)
+	if DeltaEOK(c, expected) > 1e-9 {
+		t.Fatalf("Wrong color: %s", c.Hex())
+	}
+
+	c, err = ParseColorVars("var(--missing, red)", vars, false)
+	if err != nil || c.Hex() != "#ff0000" {
+		t.Fatalf("Fallback should be used: %v %v", c, err)
+	}
+
+	for _, cstr := range []string{"var(--missing)", "var(--loop-a)"} {
+		if _, err := ParseColorVars(cstr, vars, false); err == nil {
+			t.Fatalf("%s should fail", cstr)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+var TailwindLabels = []string{"50", "100", "200", "300", "400", "500", "600", "700", "800", "900", "950"}
+
+type ScaleOptions struct {
+	// number of steps, defaults to 11
+	Steps int
+	// step labels, the missing ones default to the Tailwind ones for 11
+	// steps, 100, 200, ... otherwise
+	Labels []string
+	// CS_OKLCH (default) or CS_LCH
+	Space int
+	// lightness of the first and last step (0-1)
+	Lightest float64
+	Darkest  float64
+	// lightness curve between the lightest and darkest steps
+	Easing int
+	// how much the chroma is reduced towards the ends of the scale (0-1)
+	ChromaFalloff float64
+	// replace the step closest to the base color with the base color itself
+	KeepBase bool
+}
+
+type ScaleStep struct {
+	Label string
+	Color RepaColor
+}
+
+// Generate a light to dark scale keeping the hue of the color. The chroma is
+// the highest at the lightness of the base color and eases towards the ends.
+func Scale(col RepaColor, options ScaleOptions) []ScaleStep {
+	if options.Steps <= 0 {
+		options.Steps = 11
+	}
+	if options.Space != CS_LCH {
+		options.Space = CS_OKLCH
+	}
+	if options.Lightest == 0 && options.Darkest == 0 {
+		options.Lightest = .97
+		options.Darkest = .27
+	}
+	labels := make([]string, options.Steps)
+	copy(labels, options.Labels)
+	for i := len(options.Labels); i < options.Steps; i++ {
+		if options.Steps == len(TailwindLabels) {
+			labels[i] = TailwindLabels[i]
+		} else {
+			labels[i] = fmt.Sprintf("%d", (i+1)*100)
+		}
+	}
+
+	bl, bc, bh := col.Coordinates(options.Space)
+
+	steps := make([]ScaleStep, options.Steps)
+	closest := 0
+	for i := range steps {
+		t := 0.0
+		if options.Steps > 1 {
+			t = float64(i) / float64(options.Steps-1)
+		}
+		l := options.Lightest + (options.Darkest-options.Lightest)*Ease(options.Easing, t)
+
+		// distance from the base lightness, relative to the end of the scale on that side
+		end := options.Lightest
+		if l < bl {
+			end = options.Darkest
+		}
+		u := 0.0
+		if end != bl {
+			u = clamp01((l - bl) / (end - bl))
+		}
+		c := bc * (1 - clamp01(options.ChromaFalloff)*u*u)
+
+		steps[i] = ScaleStep{labels[i], CreateColor(options.Space, l, c, bh, col.A).GamutMap()}
+
+		if math.Abs(l-bl) < math.Abs(steps[closest].Color.lightness(options.Space)-bl) {
+			closest = i
+		}
+	}
+
+	if options.KeepBase {
+		steps[closest].Color = col
+	}
+
+	return steps
+}
@@ -0,0 +1,77 @@
+package color
+
+import (
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	c, _ := ParseColor("#3366cc", false)
+	steps := Scale(c, ScaleOptions{ChromaFalloff: .5})
+
+	if len(steps) != 11 {
+		t.Fatalf("Wrong number of steps: %d", len(steps))
+	}
+
+	_, _, bh := c.OkLch()
+	for i, step := range steps {
+		if step.Label != TailwindLabels[i] {
+			t.Fatalf("Wrong label: %s", step.Label)
+		}
+		if !step.Color.InGamut() {
+			t.Fatalf("Step out of gamut: %v", step.Color)
+		}
+		l, ch, h := step.Color.OkLch()
+		if ch > .03 && !almosteq_eps(h, bh, 10) {
+			t.Fatalf("Hue changed: %v => %v", bh, h)
+		}
+		if i > 0 {
+			pl, _, _ := steps[i-1].Color.OkLch()
+			if pl <= l {
+				t.Fatalf("Steps should go from light to dark")
+			}
+		}
+	}
+
+	l, _, _ := steps[0].Color.OkLch()
+	if !almosteq_eps(l, .97, .01) {
+		t.Fatalf("Wrong lightest step: %v", l)
+	}
+}
+
+func TestScaleOptions(t *testing.T) {
+	c, _ := ParseColor("#3366cc", false)
+	steps := Scale(c, ScaleOptions{Steps: 5, Space: CS_LCH, KeepBase: true})
+
+	if len(steps) != 5 || steps[0].Label != "100" || steps[4].Label != "500" {
+		t.Fatalf("Wrong steps: %v", steps)
+	}
+
+	found := false
+	for _, step := range steps {
+		if step.Color == c {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Base color is not in the scale")
+	}
+
+	// the missing labels are filled in
+	steps = Scale(c, ScaleOptions{Steps: 4, Labels: []string{"light", "base"}})
+	if len(steps) != 4 || steps[0].Label != "light" || steps[1].Label != "base" || steps[2].Label != "300" || steps[3].Label != "400" {
+		t.Fatalf("Wrong labels: %v", steps)
+	}
+}
+
+func TestEase(t *testing.T) {
+	for easing := range EasingNames {
+		if Ease(easing, 0) != 0 || Ease(easing, 1) != 1 {
+			t.Fatalf("Wrong end points for %s", EasingNames[easing])
+		}
+		for i := 1; i <= 10; i++ {
+			if Ease(easing, float64(i)/10) < Ease(easing, float64(i-1)/10) {
+				t.Fatalf("%s is not monotonic", EasingNames[easing])
+			}
+		}
+	}
+}
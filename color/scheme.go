@@ -0,0 +1,117 @@
+package color
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	SCHEME_COMPLEMENTARY       = iota
+	SCHEME_SPLIT_COMPLEMENTARY = iota
+	SCHEME_ANALOGOUS           = iota
+	SCHEME_TRIADIC             = iota
+	SCHEME_TETRADIC            = iota
+	SCHEME_MONOCHROMATIC       = iota
+)
+
+var SchemeNames = []string{"complementary", "split-complementary", "analogous", "triadic", "tetradic", "monochromatic"}
+
+// hue offsets of the schemes, the base color is always the first one
+var schemeHues = map[int][]float64{
+	SCHEME_COMPLEMENTARY:       {0, 180},
+	SCHEME_SPLIT_COMPLEMENTARY: {0, 150, 210},
+	SCHEME_ANALOGOUS:           {0, -30, 30},
+	SCHEME_TRIADIC:             {0, 120, 240},
+	SCHEME_TETRADIC:            {0, 90, 180, 270},
+}
+
+// lightness offsets of the monochromatic scheme
+var monochromaticSteps = []float64{-.3, -.15, 0, .15, .3}
+
+func ParseScheme(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for scheme, n := range SchemeNames {
+		if n == name {
+			return scheme, true
+		}
+	}
+	return 0, false
+}
+
+func normalizeHue(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// Rotate the hue of the color in the given hue space (CS_HSL, CS_LCH or CS_OKLCH)
+func (col RepaColor) RotateHue(degrees float64, mode int) RepaColor {
+	switch mode {
+	case CS_HSL:
+		h, s, l := col.Coordinates(CS_HSL)
+		return CreateColor(CS_HSL, normalizeHue(h+degrees), s, l, col.A)
+	case CS_LCH, CS_OKLCH:
+		l, c, h := col.Coordinates(mode)
+		return CreateColor(mode, l, c, normalizeHue(h+degrees), col.A)
+	}
+	return col
+}
+
+// Change the lightness of the color in the given hue space (CS_HSL, CS_LCH or CS_OKLCH)
+func (col RepaColor) SetLightness(lightness float64, mode int) RepaColor {
+	switch mode {
+	case CS_HSL:
+		h, s, _ := col.Coordinates(CS_HSL)
+		return CreateColor(CS_HSL, h, s, lightness, col.A)
+	case CS_LCH, CS_OKLCH:
+		_, c, h := col.Coordinates(mode)
+		return CreateColor(mode, lightness, c, h, col.A)
+	}
+	return col
+}
+
+func (col RepaColor) lightness(mode int) float64 {
+	switch mode {
+	case CS_HSL:
+		_, _, l := col.Coordinates(CS_HSL)
+		return l
+	case CS_LCH, CS_OKLCH:
+		l, _, _ := col.Coordinates(mode)
+		return l
+	}
+	return 0
+}
+
+// Generate a color scheme from the color, hues are rotated in the given hue
+// space (CS_HSL, CS_LCH or CS_OKLCH)
+func Scheme(col RepaColor, scheme int, mode int) []RepaColor {
+	if scheme == SCHEME_MONOCHROMATIC {
+		l := col.lightness(mode)
+		colors := make([]RepaColor, 0, len(monochromaticSteps))
+		for _, step := range monochromaticSteps {
+			if step == 0 {
+				colors = append(colors, col)
+				continue
+			}
+			colors = append(colors, col.SetLightness(clamp01(l+step), mode))
+		}
+		return colors
+	}
+
+	hues, ok := schemeHues[scheme]
+	if !ok {
+		return []RepaColor{col}
+	}
+
+	colors := make([]RepaColor, 0, len(hues))
+	for _, hue := range hues {
+		if hue == 0 {
+			colors = append(colors, col)
+			continue
+		}
+		colors = append(colors, col.RotateHue(hue, mode))
+	}
+	return colors
+}
@@ -0,0 +1,51 @@
+package color
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRotateHue(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		c := CreateColor(CS_RGB, rand.Float64(), rand.Float64(), rand.Float64(), 1)
+		for _, mode := range []int{CS_HSL, CS_LCH, CS_OKLCH} {
+			r := c.RotateHue(90, mode).RotateHue(270, mode)
+			if !almosteq(c.R, r.R) || !almosteq(c.G, r.G) || !almosteq(c.B, r.B) {
+				t.Fatalf("Full rotation changed the color: %v => %v", c, r)
+			}
+		}
+	}
+
+	c, _ := ParseColor("oklch(70% 0.1 300)", false)
+	if s := c.RotateHue(90, CS_OKLCH).OkLchString(); s != "oklch(70% 0.1 30)" {
+		t.Fatalf("Wrong rotated color: %s", s)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	c := CreateColor(CS_HSL, 30, .8, .5, 1)
+	lengths := []int{2, 3, 3, 3, 4, 5}
+	for scheme, n := range lengths {
+		colors := Scheme(c, scheme, CS_HSL)
+		if len(colors) != n {
+			t.Fatalf("Wrong number of colors in %s: %d", SchemeNames[scheme], len(colors))
+		}
+	}
+
+	complementary := Scheme(c, SCHEME_COMPLEMENTARY, CS_HSL)
+	if complementary[0] != c {
+		t.Fatalf("First color should be the base color")
+	}
+	if h, _, _ := complementary[1].Coordinates(CS_HSL); h != 210 {
+		t.Fatalf("Wrong complementary hue: %v", h)
+	}
+
+	mono := Scheme(c, SCHEME_MONOCHROMATIC, CS_OKLCH)
+	for i := 1; i < len(mono); i++ {
+		l1, _, _ := mono[i-1].OkLch()
+		l2, _, _ := mono[i].OkLch()
+		if l1 >= l2 {
+			t.Fatalf("Monochromatic colors should be ordered by lightness")
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package color
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+type matrix3 [3][3]float64
+
+func (m matrix3) mul(v1, v2, v3 float64) (float64, float64, float64) {
+	return m[0][0]*v1 + m[0][1]*v2 + m[0][2]*v3,
+		m[1][0]*v1 + m[1][1]*v2 + m[1][2]*v3,
+		m[2][0]*v1 + m[2][1]*v2 + m[2][2]*v3
+}
+
+// Bradford chromatic adaptation between the D50 and D65 white points
+var d50ToD65 = matrix3{
+	{0.955473421488075, -0.02309845494876471, 0.06325924320057072},
+	{-0.0283697093338637, 1.0099953980813041, 0.021041441191917323},
+	{0.012314014864481998, -0.020507649298898964, 1.330365926242124},
+}
+
+var d65ToD50 = matrix3{
+	{1.0479297925449969, 0.022946870601609652, -0.05019226628920524},
+	{0.02962780877005599, 0.9904344267538799, -0.017073799063418826},
+	{-0.009243040646204504, 0.015055191490298152, 0.7518742814281371},
+}
+
+// RGB color space defined by its transfer functions and primaries
+// Matrices and transfer functions are from the CSS Color 4 sample code
+type rgbSpace struct {
+	name       string
+	toLinear   func(float64) float64
+	fromLinear func(float64) float64
+	toXyz      matrix3
+	fromXyz    matrix3
+	d50        bool
+}
+
+func srgbToLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Copysign(math.Pow((a+0.055)/1.055, 2.4), v)
+}
+
+func srgbFromLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.0031308 {
+		return v * 12.92
+	}
+	return math.Copysign(1.055*math.Pow(a, 1/2.4)-0.055, v)
+}
+
+const rec2020Alpha = 1.09929682680944
+const rec2020Beta = 0.018053968510807
+
+func rec2020ToLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a < rec2020Beta*4.5 {
+		return v / 4.5
+	}
+	return math.Copysign(math.Pow((a+rec2020Alpha-1)/rec2020Alpha, 1/0.45), v)
+}
+
+func rec2020FromLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= rec2020Beta {
+		return v * 4.5
+	}
+	return math.Copysign(rec2020Alpha*math.Pow(a, 0.45)-(rec2020Alpha-1), v)
+}
+
+func a98ToLinear(v float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), 563.0/256.0), v)
+}
+
+func a98FromLinear(v float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), 256.0/563.0), v)
+}
+
+func prophotoToLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 16.0/512.0 {
+		return v / 16
+	}
+	return math.Copysign(math.Pow(a, 1.8), v)
+}
+
+func prophotoFromLinear(v float64) float64 {
+	a := math.Abs(v)
+	if a >= 1.0/512.0 {
+		return math.Copysign(math.Pow(a, 1/1.8), v)
+	}
+	return v * 16
+}
+
+var rgbSpaces = map[int]rgbSpace{
+	CS_DISPLAYP3: {
+		name:       "display-p3",
+		toLinear:   srgbToLinear,
+		fromLinear: srgbFromLinear,
+		toXyz: matrix3{
+			{0.4865709486482162, 0.26566769316909306, 0.1982172852343625},
+			{0.2289745640697488, 0.6917385218365064, 0.079286914093745},
+			{0, 0.04511338185890264, 1.043944368900976},
+		},
+		fromXyz: matrix3{
+			{2.493496911941425, -0.9313836179191239, -0.40271078445071684},
+			{-0.8294889695615747, 1.7626640603183463, 0.023624685841943577},
+			{0.03584583024378447, -0.07617238926804182, 0.9568845240076872},
+		},
+	},
+	CS_REC2020: {
+		name:       "rec2020",
+		toLinear:   rec2020ToLinear,
+		fromLinear: rec2020FromLinear,
+		toXyz: matrix3{
+			{0.6369580483012914, 0.14461690358620832, 0.1688809751641721},
+			{0.2627002120112671, 0.6779980715188708, 0.05930171646986196},
+			{0, 0.028072693049087428, 1.060985057710791},
+		},
+		fromXyz: matrix3{
+			{1.7166511879712674, -0.35567078377639233, -0.25336628137365974},
+			{-0.6666843518324892, 1.6164812366349395, 0.01576854581391113},
+			{0.017639857445310783, -0.042770613257808524, 0.9421031212354738},
+		},
+	},
+	CS_A98RGB: {
+		name:       "a98-rgb",
+		toLinear:   a98ToLinear,
+		fromLinear: a98FromLinear,
+		toXyz: matrix3{
+			{0.5766690429101305, 0.1855582379065463, 0.1882286462349947},
+			{0.29734497525053605, 0.6273635662554661, 0.07529145849399788},
+			{0.02703136138641234, 0.07068885253582723, 0.9913375368376388},
+		},
+		fromXyz: matrix3{
+			{2.0415879038107465, -0.5650069742788596, -0.34473135077832956},
+			{-0.9692436362808795, 1.8759675015077202, 0.04155505740717557},
+			{0.013444280632031142, -0.11836239223101838, 1.0151749943912054},
+		},
+	},
+	CS_PROPHOTO: {
+		name:       "prophoto-rgb",
+		toLinear:   prophotoToLinear,
+		fromLinear: prophotoFromLinear,
+		toXyz: matrix3{
+			{0.7977604896723027, 0.13518583717574031, 0.0313493495815248},
+			{0.2880711282292934, 0.7118432178101014, 0.00008565396060525902},
+			{0, 0, 0.8251046025104601},
+		},
+		fromXyz: matrix3{
+			{1.3457989731028281, -0.25558010007997534, -0.05110628506753401},
+			{-0.5446224939028347, 1.5082327413132781, 0.02053603239147973},
+			{0, 0, 1.2119675456389454},
+		},
+		d50: true,
+	},
+}
+
+func (s rgbSpace) toColor(r, g, b float64) colorful.Color {
+	x, y, z := s.toXyz.mul(s.toLinear(r), s.toLinear(g), s.toLinear(b))
+	if s.d50 {
+		x, y, z = d50ToD65.mul(x, y, z)
+	}
+	return xyzToColor(x, y, z)
+}
+
+func (s rgbSpace) fromColor(c colorful.Color) (r, g, b float64) {
+	x, y, z := colorToXyz(c)
+	if s.d50 {
+		x, y, z = d65ToD50.mul(x, y, z)
+	}
+	r, g, b = s.fromXyz.mul(x, y, z)
+	return s.fromLinear(r), s.fromLinear(g), s.fromLinear(b)
+}
+
+// XYZ conversions keeping the sign of negative (out of gamut) channels
+func xyzToColor(x, y, z float64) colorful.Color {
+	r, g, b := colorful.XyzToLinearRgb(x, y, z)
+	return colorful.Color{R: srgbFromLinear(r), G: srgbFromLinear(g), B: srgbFromLinear(b)}
+}
+
+func colorToXyz(c colorful.Color) (x, y, z float64) {
+	return colorful.LinearRgbToXyz(srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B))
+}
+
+func (col RepaColor) rgbSpaceString(mode int) string {
+	s := rgbSpaces[mode]
+	r, g, b := col.Coordinates(mode)
+	if col.A == 1 {
+		return fmt.Sprintf("color(%s %s %s %s)", s.name, formatFloat(r), formatFloat(g), formatFloat(b))
+	}
+	return fmt.Sprintf("color(%s %s %s %s / %s)", s.name, formatFloat(r), formatFloat(g), formatFloat(b), formatFloat(col.A))
+}
+
+func (col RepaColor) DisplayP3() (r, g, b float64) {
+	return col.Coordinates(CS_DISPLAYP3)
+}
+
+func (col RepaColor) Rec2020() (r, g, b float64) {
+	return col.Coordinates(CS_REC2020)
+}
+
+func (col RepaColor) A98Rgb() (r, g, b float64) {
+	return col.Coordinates(CS_A98RGB)
+}
+
+func (col RepaColor) ProPhotoRgb() (r, g, b float64) {
+	return col.Coordinates(CS_PROPHOTO)
+}
+
+func (col RepaColor) DisplayP3String() string {
+	return col.rgbSpaceString(CS_DISPLAYP3)
+}
+
+func (col RepaColor) Rec2020String() string {
+	return col.rgbSpaceString(CS_REC2020)
+}
+
+func (col RepaColor) A98RgbString() string {
+	return col.rgbSpaceString(CS_A98RGB)
+}
+
+func (col RepaColor) ProPhotoRgbString() string {
+	return col.rgbSpaceString(CS_PROPHOTO)
+}
+
+// CSS color(srgb-linear r g b)
+func (col RepaColor) LinearRgbString() string {
+	r, g, b := col.Coordinates(CS_LINEARRGB)
+	if col.A == 1 {
+		return fmt.Sprintf("color(srgb-linear %s %s %s)", formatFloat(r), formatFloat(g), formatFloat(b))
+	}
+	return fmt.Sprintf("color(srgb-linear %s %s %s / %s)", formatFloat(r), formatFloat(g), formatFloat(b), formatFloat(col.A))
+}
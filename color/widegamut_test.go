@@ -0,0 +1,57 @@
+package color
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestWideGamutRoundTrip(t *testing.T) {
+	for _, mode := range []int{CS_DISPLAYP3, CS_REC2020, CS_A98RGB, CS_PROPHOTO} {
+		for i := 0; i < 100; i++ {
+			r, g, b := rand.Float64(), rand.Float64(), rand.Float64()
+			c := CreateColor(mode, r, g, b, 1)
+			r2, g2, b2 := rgbSpaces[mode].fromColor(c.Color)
+			if !almosteq_eps(r, r2, 1e-9) || !almosteq_eps(g, g2, 1e-9) || !almosteq_eps(b, b2, 1e-9) {
+				t.Fatalf("%s round trip failed: %v %v %v => %v %v %v", rgbSpaces[mode].name, r, g, b, r2, g2, b2)
+			}
+		}
+	}
+}
+
+func TestWideGamutWhite(t *testing.T) {
+	for _, mode := range []int{CS_DISPLAYP3, CS_REC2020, CS_A98RGB, CS_PROPHOTO} {
+		c := CreateColor(mode, 1, 1, 1, 1)
+		if !almosteq_eps(c.R, 1, 1e-4) || !almosteq_eps(c.G, 1, 1e-4) || !almosteq_eps(c.B, 1, 1e-4) {
+			t.Fatalf("%s white is not white: %v %v %v", rgbSpaces[mode].name, c.R, c.G, c.B)
+		}
+	}
+}
+
+func TestDisplayP3(t *testing.T) {
+	r, g, b := CreateColor(CS_RGB, 1, 0, 0, 1).DisplayP3()
+	if !almosteq_eps(r, 0.9175, 1e-3) || !almosteq_eps(g, 0.2003, 1e-3) || !almosteq_eps(b, 0.1386, 1e-3) {
+		t.Fatalf("Wrong display-p3 value for red: %v %v %v", r, g, b)
+	}
+
+	c := CreateColor(CS_DISPLAYP3, 1, 0, 0, 1)
+	if c.InGamut() {
+		t.Fatalf("display-p3 red should be out of sRGB gamut: %v %v %v", c.R, c.G, c.B)
+	}
+	if c.DisplayP3String() != "color(display-p3 1 0 0)" {
+		t.Fatalf("Wrong display-p3 representation: %s", c.DisplayP3String())
+	}
+}
+
+func TestDisplayP3OutOfGamut(t *testing.T) {
+	// negative channels keep the CSS sRGB transfer function (with their sign)
+	c, err := ParseColor("color(display-p3 0 1 0)", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almosteq_eps(c.R, -0.5116, 1e-3) || !almosteq_eps(c.G, 1.0183, 1e-3) || !almosteq_eps(c.B, -0.3107, 1e-3) {
+		t.Fatalf("Wrong sRGB value of display-p3 green: %v %v %v", c.R, c.G, c.B)
+	}
+	if r, g, b := rgbSpaces[CS_DISPLAYP3].fromColor(c.Color); !almosteq_eps(r, 0, 1e-9) || !almosteq_eps(g, 1, 1e-9) || !almosteq_eps(b, 0, 1e-9) {
+		t.Fatalf("display-p3 green round trip failed: %v %v %v", r, g, b)
+	}
+}
@@ -170,3 +170,14 @@ func AnsiGradient(c1, c2 color.RepaColor, width, mode int) string {
 	sb.WriteString(color.ANSI_RESET)
 	return sb.String()
 }
+
+// Render a multi-stop gradient as a `width` wide ANSI bar
+func AnsiMultiGradient(g color.Gradient, width int) string {
+	var sb strings.Builder
+	for _, c := range g.Sample(width) {
+		sb.WriteString(c.AnsiBg())
+		sb.WriteString(" ")
+	}
+	sb.WriteString(color.ANSI_RESET)
+	return sb.String()
+}
@@ -8,9 +8,18 @@ import (
 )
 
 func TextColorDetails(c color.RepaColor) string {
-	nameStr, _ := color.GetName(c)
+	nameStr, ok := color.GetName(c)
+	if !ok {
+		if match, found := color.NearestName(c); found {
+			nameStr = fmt.Sprintf("~%s (ΔE %.1f)", match.Name, match.Distance)
+		}
+	}
+	gamutStr := "in sRGB gamut"
+	if !c.InGamut() {
+		gamutStr = "out of sRGB gamut"
+	}
 
-	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n", nameStr, c.Hex(), c.RgbString(), c.HslString(), c.LabString(), c.LchString(), c.OkLabString(), c.OkLchString())
+	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n", nameStr, c.Hex(), c.RgbString(), c.HslString(), c.LabString(), c.LchString(), c.OkLabString(), c.OkLchString(), c.DisplayP3String(), gamutStr)
 }
 
 func MergeStringsVertically(a, b string, width int) string {
@@ -0,0 +1,310 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/palette"
+	"github.com/dyuri/repacolor/theme"
+)
+
+// Structured output (--output json, yaml or ndjson)
+//
+// Every command emits a list of records: a JSON array, a YAML sequence or one
+// JSON object per line. The field names below are the documented schema,
+// they are only changed in a backwards compatible way.
+
+var OutputFormats = []string{"text", "json", "yaml", "ndjson"}
+
+func IsOutputFormat(format string) bool {
+	for _, f := range OutputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+type NameDocument struct {
+	Name       string  `json:"name" yaml:"name"`
+	Dictionary string  `json:"dictionary" yaml:"dictionary"`
+	Hex        string  `json:"hex" yaml:"hex"`
+	Distance   float64 `json:"distance" yaml:"distance"`
+}
+
+type CvdDocument struct {
+	Deficiency string `json:"deficiency" yaml:"deficiency"`
+	Hex        string `json:"hex" yaml:"hex"`
+	// CIEDE2000 distance between the original and the simulated color
+	Distance float64 `json:"distance,omitempty" yaml:"distance,omitempty"`
+}
+
+// A color in every supported space. Units follow CSS: rgb 0-255, hsl
+// degrees and percentages, lab/lch 0-100 lightness, oklab/oklch 0-1
+// lightness, xyz and display-p3 0-1.
+type ColorDocument struct {
+	Input string `json:"input,omitempty" yaml:"input,omitempty"`
+	// how the input was interpreted, if it wasn't a CSS color
+	Match     string        `json:"match,omitempty" yaml:"match,omitempty"`
+	Hex       string        `json:"hex" yaml:"hex"`
+	Alpha     float64       `json:"alpha" yaml:"alpha"`
+	Name      string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Nearest   *NameDocument `json:"nearest,omitempty" yaml:"nearest,omitempty"`
+	InGamut   bool          `json:"inGamut" yaml:"inGamut"`
+	RGB       [3]float64    `json:"rgb" yaml:"rgb,flow"`
+	HSL       [3]float64    `json:"hsl" yaml:"hsl,flow"`
+	Lab       [3]float64    `json:"lab" yaml:"lab,flow"`
+	LCH       [3]float64    `json:"lch" yaml:"lch,flow"`
+	OKLab     [3]float64    `json:"oklab" yaml:"oklab,flow"`
+	OKLCH     [3]float64    `json:"oklch" yaml:"oklch,flow"`
+	XYZ       [3]float64    `json:"xyz" yaml:"xyz,flow"`
+	DisplayP3 [3]float64    `json:"displayP3" yaml:"displayP3,flow"`
+	CVD       []CvdDocument `json:"cvd,omitempty" yaml:"cvd,omitempty"`
+}
+
+type DistanceDocument struct {
+	RGB       float64 `json:"rgb" yaml:"rgb"`
+	CIE76     float64 `json:"cie76" yaml:"cie76"`
+	CIE94     float64 `json:"cie94" yaml:"cie94"`
+	CIEDE2000 float64 `json:"ciede2000" yaml:"ciede2000"`
+}
+
+type ContrastDocument struct {
+	WCAG2 float64 `json:"wcag2" yaml:"wcag2"`
+	// APCA Lc of the color on the reference, and of the reference on the color
+	APCA        float64 `json:"apca" yaml:"apca"`
+	APCAReverse float64 `json:"apcaReverse" yaml:"apcaReverse"`
+}
+
+type CompareDocument struct {
+	Reference ColorDocument    `json:"reference" yaml:"reference"`
+	Color     ColorDocument    `json:"color" yaml:"color"`
+	Distance  DistanceDocument `json:"distance" yaml:"distance"`
+	Contrast  ContrastDocument `json:"contrast" yaml:"contrast"`
+}
+
+type LevelDocument struct {
+	Level string  `json:"level" yaml:"level"`
+	Ratio float64 `json:"ratio" yaml:"ratio"`
+	Pass  bool    `json:"pass" yaml:"pass"`
+}
+
+type ContrastPairDocument struct {
+	Foreground string          `json:"foreground" yaml:"foreground"`
+	Background string          `json:"background" yaml:"background"`
+	Ratio      float64         `json:"ratio" yaml:"ratio"`
+	APCA       float64         `json:"apca" yaml:"apca"`
+	Levels     []LevelDocument `json:"levels" yaml:"levels"`
+	// required level and its verdict
+	Required string `json:"required,omitempty" yaml:"required,omitempty"`
+	Pass     bool   `json:"pass" yaml:"pass"`
+	// closest passing foreground (contrast --fix)
+	Suggestion string `json:"suggestion,omitempty" yaml:"suggestion,omitempty"`
+}
+
+type SchemeDocument struct {
+	Scheme string          `json:"scheme" yaml:"scheme"`
+	Colors []ColorDocument `json:"colors" yaml:"colors"`
+}
+
+type ScaleStepDocument struct {
+	Label string        `json:"label" yaml:"label"`
+	Color ColorDocument `json:"color" yaml:"color"`
+}
+
+type GradientStopDocument struct {
+	Position float64       `json:"position" yaml:"position"`
+	Color    ColorDocument `json:"color" yaml:"color"`
+}
+
+type GradientDocument struct {
+	Space   string                 `json:"space" yaml:"space"`
+	Hue     string                 `json:"hue" yaml:"hue"`
+	Easing  string                 `json:"easing" yaml:"easing"`
+	Stops   []GradientStopDocument `json:"stops" yaml:"stops"`
+	Samples []ColorDocument        `json:"samples,omitempty" yaml:"samples,omitempty"`
+}
+
+type NamesDocument struct {
+	Input   string         `json:"input" yaml:"input"`
+	Hex     string         `json:"hex" yaml:"hex"`
+	Matches []NameDocument `json:"matches" yaml:"matches"`
+}
+
+type PaletteEntryDocument struct {
+	Name  string        `json:"name" yaml:"name"`
+	Color ColorDocument `json:"color" yaml:"color"`
+}
+
+type PaletteDocument struct {
+	Name   string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Colors []PaletteEntryDocument `json:"colors" yaml:"colors"`
+}
+
+type TokenDocument struct {
+	Path string `json:"path" yaml:"path"`
+	// path of the aliased token
+	Alias string        `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Color ColorDocument `json:"color" yaml:"color"`
+}
+
+type ThemeColorDocument struct {
+	// background, foreground, cursor, cursor-text, selection-background,
+	// selection-foreground or the ANSI color name (black ... bright-white)
+	Name  string        `json:"name" yaml:"name"`
+	Color ColorDocument `json:"color" yaml:"color"`
+	// WCAG 2 contrast ratio on the background
+	Contrast float64 `json:"contrast" yaml:"contrast"`
+}
+
+type ThemeDocument struct {
+	Name   string               `json:"name,omitempty" yaml:"name,omitempty"`
+	Colors []ThemeColorDocument `json:"colors" yaml:"colors"`
+}
+
+type ExtractDocument struct {
+	Color ColorDocument `json:"color" yaml:"color"`
+	// share of the (weighted) pixels of the image, 0-1
+	Weight float64 `json:"weight" yaml:"weight"`
+}
+
+// Round to 4 decimals to avoid floating point noise in the output
+func round4(v float64) float64 {
+	v = math.Round(v*10000) / 10000
+	if v == 0 {
+		// no negative zeros
+		return 0
+	}
+	return v
+}
+
+func coords(v1, v2, v3 float64, s1, s2, s3 float64) [3]float64 {
+	return [3]float64{round4(v1 * s1), round4(v2 * s2), round4(v3 * s3)}
+}
+
+func NewNameDocument(m color.NameMatch) NameDocument {
+	return NameDocument{m.Name, m.Dictionary, m.Color.Hex(), round4(m.Distance)}
+}
+
+func NewColorDocument(c color.RepaColor) ColorDocument {
+	doc := ColorDocument{
+		Hex:     c.Hex(),
+		Alpha:   round4(c.A),
+		InGamut: c.InGamut(),
+	}
+
+	if name, ok := color.GetName(c); ok {
+		doc.Name = name
+	} else if m, ok := color.NearestName(c); ok {
+		nd := NewNameDocument(m)
+		doc.Nearest = &nd
+	}
+
+	g := c.ToGamut()
+	doc.RGB = coords(g.R, g.G, g.B, 255, 255, 255)
+	h, s, l := c.Coordinates(color.CS_HSL)
+	doc.HSL = coords(h, s, l, 1, 100, 100)
+	lab1, lab2, lab3 := c.Coordinates(color.CS_LAB)
+	doc.Lab = coords(lab1, lab2, lab3, 100, 100, 100)
+	lch1, lch2, lch3 := c.Coordinates(color.CS_LCH)
+	doc.LCH = coords(lch1, lch2, lch3, 100, 100, 1)
+	ok1, ok2, ok3 := c.Coordinates(color.CS_OKLAB)
+	doc.OKLab = coords(ok1, ok2, ok3, 1, 1, 1)
+	okl1, okl2, okl3 := c.Coordinates(color.CS_OKLCH)
+	doc.OKLCH = coords(okl1, okl2, okl3, 1, 1, 1)
+	x, y, z := c.Coordinates(color.CS_XYZ)
+	doc.XYZ = coords(x, y, z, 1, 1, 1)
+	p1, p2, p3 := c.DisplayP3()
+	doc.DisplayP3 = coords(p1, p2, p3, 1, 1, 1)
+
+	return doc
+}
+
+// Color document with the input and its interpretation
+func NewColorInfoDocument(input string, info color.ColorInfo) ColorDocument {
+	doc := NewColorDocument(info.Color)
+	doc.Input = input
+	doc.Match = info.Description()
+	return doc
+}
+
+// The color as seen with the given color vision deficiencies
+func NewCvdDocuments(c color.RepaColor, kinds []int, severity float64, method int) []CvdDocument {
+	var docs []CvdDocument
+	for _, kind := range kinds {
+		sim := c.SimulateCVD(kind, severity, method)
+		docs = append(docs, CvdDocument{color.CVDNames[kind], sim.Hex(), round4(c.DistanceCIEDE2000(sim.Color) * 100)})
+	}
+	return docs
+}
+
+func NewCompareDocument(c1, c2 color.RepaColor) CompareDocument {
+	return CompareDocument{
+		Reference: NewColorDocument(c1),
+		Color:     NewColorDocument(c2),
+		Distance: DistanceDocument{
+			RGB:       round4(c1.DistanceRgb(c2.Color)),
+			CIE76:     round4(c1.DistanceCIE76(c2.Color)),
+			CIE94:     round4(c1.DistanceCIE94(c2.Color)),
+			CIEDE2000: round4(c1.DistanceCIEDE2000(c2.Color)),
+		},
+		Contrast: ContrastDocument{
+			WCAG2:       round4(c1.ContrastRatio(c2)),
+			APCA:        round4(c2.APCAContrast(c1)),
+			APCAReverse: round4(c1.APCAContrast(c2)),
+		},
+	}
+}
+
+func NewPaletteDocument(p palette.Palette) PaletteDocument {
+	doc := PaletteDocument{Name: p.Name, Colors: []PaletteEntryDocument{}}
+	for _, e := range p.Colors {
+		doc.Colors = append(doc.Colors, PaletteEntryDocument{e.Label(), NewColorDocument(e.Color)})
+	}
+	return doc
+}
+
+func NewThemeDocument(t theme.Theme) ThemeDocument {
+	doc := ThemeDocument{Name: t.Name}
+	for _, nc := range t.Colors() {
+		doc.Colors = append(doc.Colors, ThemeColorDocument{nc.Name, NewColorDocument(nc.Color), round4(nc.Color.ContrastRatio(t.Background))})
+	}
+	return doc
+}
+
+// Write the records in the given format (json, yaml or ndjson)
+func WriteDocuments(w io.Writer, format string, docs []any) error {
+	if docs == nil {
+		docs = []any{}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, doc := range docs {
+			if err := enc.Encode(doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(docs); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	return fmt.Errorf("unknown output format: %s", format)
+}
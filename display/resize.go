@@ -0,0 +1,227 @@
+package display
+
+import (
+	"image"
+	imgcolor "image/color"
+	"math"
+	"strings"
+)
+
+// Image resampling for the terminal renderer. The filters are separable, the
+// kernels are stretched when downscaling, so every source pixel contributes
+// (box becomes an area average). Colors are filtered premultiplied.
+
+const (
+	FILTER_NEAREST    = iota
+	FILTER_BOX        = iota
+	FILTER_BILINEAR   = iota
+	FILTER_CATMULLROM = iota
+	FILTER_LANCZOS    = iota
+)
+
+var FilterNames = []string{"nearest", "box", "bilinear", "catmull-rom", "lanczos"}
+
+type resampleFilter struct {
+	support float64
+	kernel  func(x float64) float64
+}
+
+var resampleFilters = []resampleFilter{
+	FILTER_NEAREST: {.5, func(x float64) float64 { return 1 }},
+	FILTER_BOX: {.5, func(x float64) float64 {
+		if math.Abs(x) <= .5 {
+			return 1
+		}
+		return 0
+	}},
+	FILTER_BILINEAR: {1, func(x float64) float64 {
+		return math.Max(0, 1-math.Abs(x))
+	}},
+	FILTER_CATMULLROM: {2, func(x float64) float64 {
+		x = math.Abs(x)
+		switch {
+		case x < 1:
+			return 1.5*x*x*x - 2.5*x*x + 1
+		case x < 2:
+			return -.5*x*x*x + 2.5*x*x - 4*x + 2
+		}
+		return 0
+	}},
+	FILTER_LANCZOS: {3, func(x float64) float64 {
+		if x == 0 {
+			return 1
+		}
+		if math.Abs(x) >= 3 {
+			return 0
+		}
+		px := math.Pi * x
+		return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+	}},
+}
+
+func ParseFilter(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "catmullrom", "bicubic", "cubic":
+		return FILTER_CATMULLROM, true
+	case "linear", "triangle":
+		return FILTER_BILINEAR, true
+	case "area":
+		return FILTER_BOX, true
+	case "lanczos3":
+		return FILTER_LANCZOS, true
+	}
+	for filter, n := range FilterNames {
+		if n == name {
+			return filter, true
+		}
+	}
+	return 0, false
+}
+
+// Source pixels and their weights of a destination pixel
+type resampleTaps struct {
+	start   int
+	weights []float32
+}
+
+// Taps of the destination pixels along one axis
+func resampleWeights(src, dst int, filter int) []resampleTaps {
+	taps := make([]resampleTaps, dst)
+	ratio := float64(src) / float64(dst)
+
+	if filter == FILTER_NEAREST {
+		for i := range taps {
+			taps[i] = resampleTaps{min(int((float64(i)+.5)*ratio), src-1), []float32{1}}
+		}
+		return taps
+	}
+
+	f := resampleFilters[filter]
+	scale := math.Max(ratio, 1)
+	support := f.support * scale
+	for i := range taps {
+		center := (float64(i)+.5)*ratio - .5
+		start := int(math.Ceil(center - support))
+		end := int(math.Floor(center + support))
+		if filter == FILTER_BOX && end-start >= 1 && math.Abs(float64(end)-center) == support {
+			// half open box, so the neighbouring pixels don't overlap
+			end--
+		}
+
+		weights := make([]float32, 0, end-start+1)
+		sum := 0.0
+		for j := start; j <= end; j++ {
+			w := f.kernel((float64(j) - center) / scale)
+			weights = append(weights, float32(w))
+			sum += w
+		}
+		if sum != 0 {
+			for j := range weights {
+				weights[j] = float32(float64(weights[j]) / sum)
+			}
+		}
+		taps[i] = resampleTaps{start, weights}
+	}
+	return taps
+}
+
+// Resample the image to width x height pixels
+func ResizeImage(img image.Image, width, height int, filter int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 || srcWidth == 0 || srcHeight == 0 {
+		return dst
+	}
+	if filter < 0 || filter >= len(resampleFilters) {
+		filter = FILTER_CATMULLROM
+	}
+
+	clamp := func(i, n int) int {
+		return min(max(i, 0), n-1)
+	}
+
+	// horizontal pass, row by row: srcHeight x width premultiplied pixels
+	xtaps := resampleWeights(srcWidth, width, filter)
+	rows := make([][]float32, srcHeight)
+	row := make([]float32, srcWidth*4)
+	for y := 0; y < srcHeight; y++ {
+		for x := 0; x < srcWidth; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			row[x*4], row[x*4+1], row[x*4+2], row[x*4+3] = float32(r), float32(g), float32(b), float32(a)
+		}
+		out := make([]float32, width*4)
+		for x, t := range xtaps {
+			for j, w := range t.weights {
+				sx := clamp(t.start+j, srcWidth) * 4
+				for k := 0; k < 4; k++ {
+					out[x*4+k] += row[sx+k] * w
+				}
+			}
+		}
+		rows[y] = out
+	}
+
+	// vertical pass
+	ytaps := resampleWeights(srcHeight, height, filter)
+	var px [4]float32
+	for y, t := range ytaps {
+		for x := 0; x < width; x++ {
+			px = [4]float32{}
+			for j, w := range t.weights {
+				src := rows[clamp(t.start+j, srcHeight)]
+				for k := 0; k < 4; k++ {
+					px[k] += src[x*4+k] * w
+				}
+			}
+
+			a := math.Min(math.Max(float64(px[3]), 0), 0xffff)
+			if a == 0 {
+				continue
+			}
+			unpremultiply := func(v float32) uint8 {
+				return uint8(math.Round(math.Min(math.Max(float64(v)/a, 0), 1) * 255))
+			}
+			dst.SetNRGBA(x, y, imgcolor.NRGBA{unpremultiply(px[0]), unpremultiply(px[1]), unpremultiply(px[2]), uint8(math.Round(a / 0xffff * 255))})
+		}
+	}
+
+	return dst
+}
+
+// Largest size with the aspect ratio of the image fitting in maxWidth x
+// maxHeight pixels, images are not enlarged. pixelRatio is the height of the
+// output pixels relative to their width.
+func FitSize(width, height, maxWidth, maxHeight int, pixelRatio float64) (int, int) {
+	if width <= 0 || height <= 0 {
+		return 0, 0
+	}
+	if pixelRatio <= 0 {
+		pixelRatio = 1
+	}
+
+	w := float64(min(width, maxWidth))
+	h := w * float64(height) / float64(width) / pixelRatio
+	if h > float64(maxHeight) {
+		h = float64(maxHeight)
+		w = h * pixelRatio * float64(width) / float64(height)
+	}
+	return max(1, int(math.Round(w))), max(1, int(math.Round(h)))
+}
+
+// Apply fn to every (non transparent) pixel of the image
+func MapImage(img image.Image, fn func(c imgcolor.NRGBA) imgcolor.NRGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := imgcolor.NRGBAModel.Convert(img.At(x, y)).(imgcolor.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			dst.SetNRGBA(x-bounds.Min.X, y-bounds.Min.Y, fn(c))
+		}
+	}
+	return dst
+}
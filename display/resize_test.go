@@ -0,0 +1,101 @@
+package display
+
+import (
+	"image"
+	imgcolor "image/color"
+	"math"
+	"testing"
+)
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		width, height, maxWidth, maxHeight int
+		pixelRatio                         float64
+		w, h                               int
+	}{
+		{800, 600, 80, 100, 1, 80, 60},
+		{800, 600, 80, 30, 1, 40, 30},
+		{800, 600, 80, 100, .5, 67, 100},
+		{400, 100, 80, 100, 2, 80, 10},
+		// not enlarged
+		{10, 10, 80, 80, 1, 10, 10},
+		{0, 10, 80, 80, 1, 0, 0},
+	}
+
+	for _, test := range tests {
+		w, h := FitSize(test.width, test.height, test.maxWidth, test.maxHeight, test.pixelRatio)
+		if w != test.w || h != test.h {
+			t.Errorf("FitSize(%d, %d, %d, %d, %g) = %d, %d (vs. %d, %d)", test.width, test.height, test.maxWidth, test.maxHeight, test.pixelRatio, w, h, test.w, test.h)
+		}
+		if w > test.maxWidth || h > test.maxHeight {
+			t.Errorf("FitSize(%d, %d, ...) = %d, %d does not fit", test.width, test.height, w, h)
+		}
+	}
+
+	// the aspect ratio is kept
+	for _, size := range [][2]int{{1920, 1080}, {333, 777}, {50, 40}} {
+		w, h := FitSize(size[0], size[1], 40, 40, 1)
+		if aspect, fitted := float64(size[0])/float64(size[1]), float64(w)/float64(h); math.Abs(aspect-fitted)/aspect > .05 {
+			t.Errorf("Aspect ratio of %v changed: %d x %d", size, w, h)
+		}
+	}
+}
+
+func TestResampleWeights(t *testing.T) {
+	for filter := range FilterNames {
+		for _, size := range [][2]int{{10, 3}, {3, 10}, {7, 7}, {100, 1}, {1, 5}} {
+			for i, taps := range resampleWeights(size[0], size[1], filter) {
+				sum := float32(0)
+				for _, w := range taps.weights {
+					sum += w
+				}
+				if math.Abs(float64(sum)-1) > 1e-5 {
+					t.Errorf("%s %d => %d, pixel %d: weights sum to %v", FilterNames[filter], size[0], size[1], i, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestResizeBox(t *testing.T) {
+	// 2x2 blocks are averaged
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	values := [2][4]uint8{{0, 100, 40, 40}, {200, 100, 40, 240}}
+	for y, row := range values {
+		for x, v := range row {
+			img.SetNRGBA(x, y, imgcolor.NRGBA{v, v, v, 255})
+		}
+	}
+
+	resized := ResizeImage(img, 2, 1, FILTER_BOX)
+	for x, expected := range []uint8{100, 90} {
+		if c := resized.NRGBAAt(x, 0); c != (imgcolor.NRGBA{expected, expected, expected, 255}) {
+			t.Errorf("Wrong average at %d: %v (vs. %d)", x, c, expected)
+		}
+	}
+}
+
+func TestResizeAlpha(t *testing.T) {
+	// opaque red next to transparent green: the color of the transparent
+	// pixel must not leak into the result, only the alpha is averaged
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, imgcolor.NRGBA{255, 0, 0, 255})
+	img.SetNRGBA(1, 0, imgcolor.NRGBA{0, 255, 0, 0})
+
+	if c := ResizeImage(img, 1, 1, FILTER_BOX).NRGBAAt(0, 0); c != (imgcolor.NRGBA{255, 0, 0, 128}) {
+		t.Errorf("Wrong downscaled edge: %v", c)
+	}
+
+	for _, filter := range []int{FILTER_BILINEAR, FILTER_CATMULLROM, FILTER_LANCZOS} {
+		resized := ResizeImage(img, 8, 1, filter)
+		for x := 0; x < 8; x++ {
+			c := resized.NRGBAAt(x, 0)
+			if c.A > 0 && (c.R != 255 || c.G != 0 || c.B != 0) {
+				t.Errorf("%s: color changed at %d: %v", FilterNames[filter], x, c)
+			}
+		}
+		if first, last := resized.NRGBAAt(0, 0), resized.NRGBAAt(7, 0); first.A != 255 || last.A != 0 {
+			t.Errorf("%s: wrong alpha at the edges: %d, %d", FilterNames[filter], first.A, last.A)
+		}
+	}
+}
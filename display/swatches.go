@@ -0,0 +1,100 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Render the colors as a row of `width` wide swatches with their labels below
+func RenderSwatchRow(colors []color.RepaColor, labels []string, width int) string {
+	if width <= 0 {
+		width = 10
+	}
+
+	var swatches, texts strings.Builder
+	for i, c := range colors {
+		if i > 0 {
+			swatches.WriteString(" ")
+			texts.WriteString(" ")
+		}
+		swatches.WriteString(c.AnsiBg() + strings.Repeat(" ", width) + color.ANSI_RESET)
+
+		label := ""
+		if i < len(labels) {
+			label = labels[i]
+		}
+		if len(label) > width {
+			label = label[:width]
+		}
+		texts.WriteString(fmt.Sprintf("%-*s", width, label))
+	}
+
+	row := swatches.String()
+	return row + "\n" + row + "\n" + strings.TrimRight(texts.String(), " ")
+}
+
+// Swatches of the color as seen with each color vision deficiency
+func CvdSwatches(c color.RepaColor, kinds []int, severity float64, method int) string {
+	colors := []color.RepaColor{c}
+	labels := []string{"normal"}
+	for _, kind := range kinds {
+		colors = append(colors, c.SimulateCVD(kind, severity, method))
+		labels = append(labels, color.CVDNames[kind])
+	}
+
+	return RenderSwatchRow(colors, labels, 10)
+}
+
+// Extracted colors as swatches with their hex values and shares, above a bar
+// showing the proportions of the colors
+func RenderExtractedSwatches(swatches []color.Swatch, width int) string {
+	if width <= 0 {
+		width = 10
+	}
+	if len(swatches) == 0 {
+		return ""
+	}
+
+	// swatches are 2 lines high, separated by a transparent column, the bar
+	// below them is 1 line high
+	fullwidth := len(swatches)*(width+1) - 1
+	img := image.NewRGBA(image.Rect(0, 0, fullwidth, 6))
+	for i, s := range swatches {
+		for y := 0; y < 4; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(i*(width+1)+x, y, s.Color)
+			}
+		}
+	}
+	start := 0.0
+	for _, s := range swatches {
+		end := start + s.Weight*float64(fullwidth)
+		for x := int(math.Round(start)); x < int(math.Round(end)) && x < fullwidth; x++ {
+			img.Set(x, 4, s.Color)
+			img.Set(x, 5, s.Color)
+		}
+		start = end
+	}
+
+	var hexes, weights strings.Builder
+	for i, s := range swatches {
+		if i > 0 {
+			hexes.WriteString(" ")
+			weights.WriteString(" ")
+		}
+		hexes.WriteString(fmt.Sprintf("%-*s", width, s.Color.Hex()))
+		weights.WriteString(fmt.Sprintf("%-*s", width, fmt.Sprintf("%.1f%%", s.Weight*100)))
+	}
+
+	rows := strings.Split(RenderAnsiImage(img), "\n")
+	return strings.Join([]string{
+		rows[0], rows[1],
+		strings.TrimRight(hexes.String(), " "),
+		strings.TrimRight(weights.String(), " "),
+		rows[2],
+	}, "\n")
+}
@@ -0,0 +1,101 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dyuri/repacolor/color"
+	"github.com/dyuri/repacolor/theme"
+)
+
+// Text with its colors, NOCOLOR background is the theme background
+type span struct {
+	text string
+	fg   color.RepaColor
+	bg   color.RepaColor
+}
+
+// Sample terminal session (prompt, ls, diff, test output) rendered with the
+// colors of the theme, `width` columns wide
+func RenderThemeSample(t theme.Theme, width int) string {
+	if width <= 0 {
+		width = 64
+	}
+	a := t.ANSI
+	fg := t.Foreground
+	const (
+		black = iota
+		red
+		green
+		yellow
+		blue
+		magenta
+		cyan
+		white
+		bright
+	)
+
+	prompt := func(command string) []span {
+		return []span{
+			{"user@host", a[green], color.NOCOLOR},
+			{" ~/src/repacolor ", a[blue], color.NOCOLOR},
+			{"(main) ", a[magenta], color.NOCOLOR},
+			{"$ " + command, fg, color.NOCOLOR},
+		}
+	}
+
+	lines := [][]span{
+		prompt("ls -l"),
+		{{"drwxr-xr-x  ", fg, color.NOCOLOR}, {"cmd", a[blue], color.NOCOLOR}, {"  ", fg, color.NOCOLOR}, {"color", a[blue], color.NOCOLOR}, {"  ", fg, color.NOCOLOR}, {"theme", a[blue], color.NOCOLOR}},
+		{{"-rwxr-xr-x  ", fg, color.NOCOLOR}, {"repacolor", a[green], color.NOCOLOR}, {"  ", fg, color.NOCOLOR}, {"build.sh", a[green], color.NOCOLOR}},
+		{{"lrwxrwxrwx  ", fg, color.NOCOLOR}, {"latest", a[cyan], color.NOCOLOR}, {" -> repacolor  ", fg, color.NOCOLOR}, {"broken", a[red], a[black]}},
+		{{"-rw-r--r--  README.md  go.mod  ", fg, color.NOCOLOR}, {"demo.tar.gz", a[red], color.NOCOLOR}},
+		prompt("git diff"),
+		{{"diff --git a/theme/theme.go b/theme/theme.go", a[white], color.NOCOLOR}},
+		{{"@@ -12,7 +12,7 @@", a[cyan], color.NOCOLOR}, {" type Theme struct {", fg, color.NOCOLOR}},
+		{{"-    Name string", a[red], color.NOCOLOR}},
+		{{"+    Name, Author string", a[green], color.NOCOLOR}},
+		{{"     // the 8 normal colors", a[bright+black], color.NOCOLOR}},
+		prompt("make test"),
+		{{"ok  ", a[green], color.NOCOLOR}, {"  repacolor/theme  0.012s", fg, color.NOCOLOR}},
+		{{"FAIL", a[bright+red], color.NOCOLOR}, {"  repacolor/cmd  ", fg, color.NOCOLOR}, {"warning:", a[yellow], color.NOCOLOR}, {" 2 issues ", fg, color.NOCOLOR}, {"(see log)", a[bright+black], color.NOCOLOR}},
+		{{"selected text", t.SelectionForeground, t.SelectionBackground}, {" and the cursor", fg, color.NOCOLOR}, {" ", t.CursorText, t.Cursor}},
+		nil,
+	}
+
+	// the ANSI colors as text
+	for row := 0; row < 2; row++ {
+		var line []span
+		for i := 0; i < 8; i++ {
+			line = append(line, span{fmt.Sprintf(" %-7s", theme.ANSINames[i]), a[row*8+i], color.NOCOLOR})
+		}
+		lines = append(lines, line)
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		length := 1
+		sb.WriteString(ansiColors(fg, t.Background) + " ")
+		for _, s := range line {
+			bg := s.bg
+			if bg == color.NOCOLOR {
+				bg = t.Background
+			}
+			sb.WriteString(ansiColors(s.fg, bg) + s.text)
+			length += utf8.RuneCountInString(s.text)
+		}
+		if length < width {
+			sb.WriteString(ansiColors(fg, t.Background) + strings.Repeat(" ", width-length))
+		}
+		sb.WriteString(color.ANSI_RESET + "\n")
+	}
+
+	return sb.String()
+}
+
+// ANSI codes of the text and background colors, without the bold of
+// AnsiFg, so the sample looks like normal text
+func ansiColors(fg, bg color.RepaColor) string {
+	return bg.AnsiBg() + fg.AnsiFg() + "\033[22m"
+}
@@ -0,0 +1,121 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Photoshop color swatches (.aco)
+//
+// Big endian: a version 1 section (version, number of colors, then color
+// space and four uint16 channels per color), followed by a version 2 section
+// with the same colors and their UTF-16 names. Old readers stop after the
+// first section.
+
+const (
+	acoSpaceRGB  = 0
+	acoSpaceHSB  = 1
+	acoSpaceCMYK = 2
+	acoSpaceLab  = 7
+	acoSpaceGray = 8
+)
+
+func appendACOColor(buf *bytes.Buffer, e Entry) {
+	r, g, b := e.Color.RGB256()
+	// 0-255 is scaled to 0-65535
+	binary.Write(buf, binary.BigEndian, [5]uint16{acoSpaceRGB, uint16(r) * 257, uint16(g) * 257, uint16(b) * 257, 0})
+}
+
+// the number of colors is stored in 16 bits
+const acoMaxColors = 65535
+
+func WriteACO(w io.Writer, p Palette) error {
+	if len(p.Colors) > acoMaxColors {
+		return fmt.Errorf("aco swatches hold at most %d colors, got %d", acoMaxColors, len(p.Colors))
+	}
+
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.BigEndian, [2]uint16{1, uint16(len(p.Colors))})
+	for _, e := range p.Colors {
+		appendACOColor(&buf, e)
+	}
+
+	binary.Write(&buf, binary.BigEndian, [2]uint16{2, uint16(len(p.Colors))})
+	for _, e := range p.Colors {
+		appendACOColor(&buf, e)
+		appendUTF16(&buf, e.Label(), true)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func acoColor(v [5]uint16) (color.RepaColor, error) {
+	f := func(i int) float64 { return float64(v[i]) / 65535 }
+
+	switch v[0] {
+	case acoSpaceRGB:
+		return color.CreateColor(color.CS_RGB, f(1), f(2), f(3), 1), nil
+	case acoSpaceHSB:
+		return hsbColor(f(1)*360, f(2), f(3), 1), nil
+	case acoSpaceCMYK:
+		// 0 is full ink
+		return color.CreateColor(color.CS_RGB, f(1)*f(4), f(2)*f(4), f(3)*f(4), 1), nil
+	case acoSpaceLab:
+		// L is 0-10000, a and b are signed -12800-12700
+		l, a, b := float64(v[1])/10000, float64(int16(v[2]))/10000, float64(int16(v[3]))/10000
+		return color.CreateColor(color.CS_LAB, l, a, b, 1), nil
+	case acoSpaceGray:
+		// 0-10000, 10000 is black
+		g := 1 - float64(v[1])/10000
+		return color.CreateColor(color.CS_RGB, g, g, g, 1), nil
+	}
+	return color.NOCOLOR, fmt.Errorf("unsupported color space: %d", v[0])
+}
+
+func ReadACO(r io.Reader) (Palette, error) {
+	var p Palette
+
+	for version := uint16(1); version <= 2; version++ {
+		var header [2]uint16
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			if version == 2 && err == io.EOF {
+				// version 1 only
+				return p, nil
+			}
+			return p, errors.New("not an ACO file")
+		}
+		if header[0] != version {
+			return p, fmt.Errorf("unexpected ACO version: %d", header[0])
+		}
+
+		// the version 2 section replaces the colors of version 1
+		p.Colors = nil
+		for i := 0; i < int(header[1]); i++ {
+			var v [5]uint16
+			if err := binary.Read(r, binary.BigEndian, &v); err != nil {
+				return p, fmt.Errorf("color %d: %w", i, err)
+			}
+			c, err := acoColor(v)
+			if err != nil {
+				return p, fmt.Errorf("color %d: %w", i, err)
+			}
+
+			name := ""
+			if version == 2 {
+				if name, err = readUTF16(r, true); err != nil {
+					return p, fmt.Errorf("color %d: %w", i, err)
+				}
+			}
+			p.Add(name, c)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,206 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Adobe Swatch Exchange (.ase)
+//
+// Big endian: "ASEF", version 1.0, number of blocks, then the blocks. A color
+// block is the UTF-16 name (with length and terminating zero), the color
+// model ("RGB ", "CMYK", "LAB " or "Gray"), the channels as float32 and the
+// color type. Groups are a group start block with the name of the group, the
+// color blocks, and a group end block.
+
+const (
+	aseBlockGroupStart = 0xc001
+	aseBlockGroupEnd   = 0xc002
+	aseBlockColor      = 0x0001
+
+	aseColorGlobal = 0
+	aseColorSpot   = 1
+	aseColorNormal = 2
+
+	aseMaxBlockLength = 1 << 20
+)
+
+// UTF-16 string with its length (including the terminating zero) as uint16
+// (ase) or uint32 (aco)
+func appendUTF16(buf *bytes.Buffer, s string, wide bool) {
+	u := append(utf16.Encode([]rune(s)), 0)
+	if wide {
+		binary.Write(buf, binary.BigEndian, uint32(len(u)))
+	} else {
+		binary.Write(buf, binary.BigEndian, uint16(len(u)))
+	}
+	binary.Write(buf, binary.BigEndian, u)
+}
+
+func readUTF16(r io.Reader, wide bool) (string, error) {
+	var n uint32
+	if wide {
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return "", err
+		}
+	} else {
+		var n16 uint16
+		if err := binary.Read(r, binary.BigEndian, &n16); err != nil {
+			return "", err
+		}
+		n = uint32(n16)
+	}
+	if n > 0xffff {
+		return "", errors.New("name too long")
+	}
+
+	u := make([]uint16, n)
+	if err := binary.Read(r, binary.BigEndian, u); err != nil {
+		return "", err
+	}
+	if n > 0 && u[n-1] == 0 {
+		u = u[:n-1]
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+func aseBlock(buf *bytes.Buffer, kind uint16, block []byte) {
+	binary.Write(buf, binary.BigEndian, kind)
+	binary.Write(buf, binary.BigEndian, uint32(len(block)))
+	buf.Write(block)
+}
+
+func aseColorBlock(e Entry) []byte {
+	var block bytes.Buffer
+	appendUTF16(&block, e.Label(), false)
+	block.WriteString("RGB ")
+	g := e.Color.ToGamut()
+	binary.Write(&block, binary.BigEndian, [3]float32{float32(g.R), float32(g.G), float32(g.B)})
+	binary.Write(&block, binary.BigEndian, uint16(aseColorNormal))
+	return block.Bytes()
+}
+
+func WriteASE(w io.Writer, p Palette) error {
+	var blocks bytes.Buffer
+	count := 0
+
+	group := ""
+	for _, e := range p.Colors {
+		if e.Group != group {
+			if group != "" {
+				aseBlock(&blocks, aseBlockGroupEnd, nil)
+				count++
+			}
+			if e.Group != "" {
+				var name bytes.Buffer
+				appendUTF16(&name, e.Group, false)
+				aseBlock(&blocks, aseBlockGroupStart, name.Bytes())
+				count++
+			}
+			group = e.Group
+		}
+		aseBlock(&blocks, aseBlockColor, aseColorBlock(e))
+		count++
+	}
+	if group != "" {
+		aseBlock(&blocks, aseBlockGroupEnd, nil)
+		count++
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("ASEF")
+	binary.Write(&buf, binary.BigEndian, [2]uint16{1, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(count))
+	buf.Write(blocks.Bytes())
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func aseColor(model string, v []float32) color.RepaColor {
+	f := func(i int) float64 { return float64(v[i]) }
+
+	switch model {
+	case "RGB ":
+		return color.CreateColor(color.CS_RGB, f(0), f(1), f(2), 1)
+	case "LAB ":
+		// L is 0-1, a and b are -128-127
+		return color.CreateColor(color.CS_LAB, f(0), f(1)/100, f(2)/100, 1)
+	case "CMYK":
+		k := 1 - f(3)
+		return color.CreateColor(color.CS_RGB, (1-f(0))*k, (1-f(1))*k, (1-f(2))*k, 1)
+	}
+	// Gray
+	return color.CreateColor(color.CS_RGB, f(0), f(0), f(0), 1)
+}
+
+var aseChannels = map[string]int{"RGB ": 3, "LAB ": 3, "CMYK": 4, "Gray": 1}
+
+func ReadASE(r io.Reader) (Palette, error) {
+	var p Palette
+	var header struct {
+		Signature [4]byte
+		Version   [2]uint16
+		Blocks    uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil || string(header.Signature[:]) != "ASEF" {
+		return p, errors.New("not an ASE file")
+	}
+
+	group := ""
+	for i := uint32(0); i < header.Blocks; i++ {
+		var kind uint16
+		var length uint32
+		if err := binary.Read(r, binary.BigEndian, &kind); err != nil {
+			return p, err
+		}
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return p, err
+		}
+		if length > aseMaxBlockLength {
+			return p, fmt.Errorf("block %d: too long", i)
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return p, err
+		}
+		block := bytes.NewReader(data)
+
+		switch kind {
+		case aseBlockGroupStart:
+			name, err := readUTF16(block, false)
+			if err != nil {
+				return p, fmt.Errorf("block %d: %w", i, err)
+			}
+			group = name
+		case aseBlockGroupEnd:
+			group = ""
+		case aseBlockColor:
+			name, err := readUTF16(block, false)
+			if err != nil {
+				return p, fmt.Errorf("block %d: %w", i, err)
+			}
+			var model [4]byte
+			if _, err := io.ReadFull(block, model[:]); err != nil {
+				return p, fmt.Errorf("block %d: %w", i, err)
+			}
+			n, ok := aseChannels[string(model[:])]
+			if !ok {
+				return p, fmt.Errorf("block %d: unknown color model: %q", i, model)
+			}
+			v := make([]float32, n)
+			if err := binary.Read(block, binary.BigEndian, v); err != nil {
+				return p, fmt.Errorf("block %d: %w", i, err)
+			}
+			p.AddToGroup(group, name, aseColor(string(model[:]), v))
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,139 @@
+package palette
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// CSS, SCSS and Less files: the custom properties (--name), SCSS ($name) and
+// Less (@name) variables with color values. Variables can refer to each
+// other, the colors of rules other than :root are grouped by their selector.
+
+var cssCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/|(^|\s)//[^\n]*`)
+
+// SCSS and Less variable references, turned into var() references
+var cssVarRefRe = regexp.MustCompile(`[$@]([A-Za-z_][\w-]*)`)
+
+type cssDeclaration struct {
+	selector string
+	name     string
+	value    string
+}
+
+// Custom property and variable declarations with their (innermost) selectors
+func cssDeclarations(src string) []cssDeclaration {
+	var decls []cssDeclaration
+	var selectors []string
+	var sb strings.Builder
+
+	declaration := func() {
+		decl := strings.TrimSpace(sb.String())
+		sb.Reset()
+
+		name, value, ok := strings.Cut(decl, ":")
+		name = strings.TrimSpace(name)
+		if !ok || len(name) < 2 {
+			return
+		}
+		if !strings.HasPrefix(name, "--") && name[0] != '$' && name[0] != '@' {
+			return
+		}
+
+		selector := ""
+		if len(selectors) > 0 {
+			selector = selectors[len(selectors)-1]
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!default"))
+		decls = append(decls, cssDeclaration{selector, strings.TrimLeft(name, "-$@"), value})
+	}
+
+	depth := 0
+	for _, r := range src {
+		switch {
+		case r == '(':
+			depth++
+			sb.WriteRune(r)
+		case r == ')':
+			depth--
+			sb.WriteRune(r)
+		case depth > 0:
+			sb.WriteRune(r)
+		case r == '{':
+			selectors = append(selectors, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		case r == '}':
+			declaration()
+			if len(selectors) > 0 {
+				selectors = selectors[:len(selectors)-1]
+			}
+		case r == ';':
+			declaration()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	declaration()
+
+	return decls
+}
+
+func isRootSelector(selector string) bool {
+	switch selector {
+	case "", ":root", "html":
+		return true
+	}
+	return false
+}
+
+func ReadCSS(r io.Reader) (Palette, error) {
+	var p Palette
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return p, err
+	}
+	decls := cssDeclarations(cssCommentRe.ReplaceAllString(string(data), "$1"))
+
+	// variables of the root and of the selectors
+	scopes := map[string]map[string]string{}
+	for i, d := range decls {
+		d.value = cssVarRefRe.ReplaceAllString(d.value, "var(--$1)")
+		decls[i] = d
+
+		scope := d.selector
+		if isRootSelector(scope) {
+			scope = ""
+		}
+		if scopes[scope] == nil {
+			scopes[scope] = map[string]string{}
+		}
+		scopes[scope][d.name] = d.value
+	}
+
+	for _, d := range decls {
+		vars := scopes[""]
+		group := ""
+		if !isRootSelector(d.selector) {
+			group = d.selector
+			vars = map[string]string{}
+			for k, v := range scopes[""] {
+				vars[k] = v
+			}
+			for k, v := range scopes[d.selector] {
+				vars[k] = v
+			}
+		}
+
+		// only real CSS colors, not color names with typos
+		info, err := color.ParseColorInfo(d.value, vars, false)
+		if err != nil || info.Source != color.SOURCE_CSS {
+			continue
+		}
+		p.AddToGroup(group, d.name, info.Color)
+	}
+
+	return p, nil
+}
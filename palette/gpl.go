@@ -0,0 +1,78 @@
+package palette
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// GIMP palette (.gpl), also used by Inkscape and Krita
+//
+//	GIMP Palette
+//	Name: <name>
+//	#
+//	R G B<tab>name
+func WriteGPL(w io.Writer, p Palette) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "GIMP Palette")
+	if p.Name != "" {
+		fmt.Fprintf(bw, "Name: %s\n", p.Name)
+	}
+	fmt.Fprintln(bw, "#")
+	for _, e := range p.Colors {
+		r, g, b := e.Color.RGB256()
+		fmt.Fprintf(bw, "%3d %3d %3d\t%s\n", r, g, b, e.Label())
+	}
+
+	return bw.Flush()
+}
+
+func ReadGPL(r io.Reader) (Palette, error) {
+	var p Palette
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "GIMP Palette" {
+		return p, errors.New("not a GIMP palette")
+	}
+
+	for lineNo := 2; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "Name:"); ok {
+			p.Name = strings.TrimSpace(name)
+			continue
+		}
+		if strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return p, fmt.Errorf("line %d: invalid color: %s", lineNo, line)
+		}
+		var rgb [3]float64
+		for i := range rgb {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil || v < 0 || v > 255 {
+				return p, fmt.Errorf("line %d: invalid color: %s", lineNo, line)
+			}
+			rgb[i] = float64(v) / 255
+		}
+		name := strings.Join(fields[3:], " ")
+		// GIMP calls unnamed colors "Untitled"
+		if strings.EqualFold(name, "untitled") {
+			name = ""
+		}
+		p.Add(name, color.CreateColor(color.CS_RGB, rgb[0], rgb[1], rgb[2], 1))
+	}
+
+	return p, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package palette
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Paint.NET palette (.txt): one AARRGGBB hex color per line, comments start
+// with a semicolon. Paint.NET reads at most 96 colors.
+
+const paintNETMaxColors = 96
+
+func WritePaintNET(w io.Writer, p Palette) error {
+	if len(p.Colors) > paintNETMaxColors {
+		return fmt.Errorf("paint.net palettes hold at most %d colors, got %d", paintNETMaxColors, len(p.Colors))
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "; paint.net Palette File")
+	if p.Name != "" {
+		fmt.Fprintf(bw, "; %s\n", p.Name)
+	}
+	for _, e := range p.Colors {
+		r, g, b, a := e.Color.RGBA256()
+		fmt.Fprintf(bw, "%02X%02X%02X%02X\n", a, r, g, b)
+	}
+
+	return bw.Flush()
+}
+
+// Colors can also be RRGGBB, the comments are ignored
+func ReadPaintNET(r io.Reader) (Palette, error) {
+	var p Palette
+	scanner := bufio.NewScanner(r)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		v, err := hex.DecodeString(line)
+		if err != nil || (len(v) != 3 && len(v) != 4) {
+			return p, fmt.Errorf("line %d: invalid color: %s", lineNo, line)
+		}
+		if len(v) == 3 {
+			v = append([]byte{0xff}, v...)
+		}
+		p.Add("", color.CreateColor(color.CS_RGB, float64(v[1])/255, float64(v[2])/255, float64(v[3])/255, float64(v[0])/255))
+	}
+
+	return p, scanner.Err()
+}
@@ -0,0 +1,237 @@
+// Package palette reads and writes color palettes in the file formats of
+// design and paint applications.
+package palette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+type Entry struct {
+	Name  string
+	Color color.RepaColor
+	// name of the group (folder) of the entry, empty if it's not grouped
+	Group string
+	// path of the design token the color refers to (see ReadDTCG)
+	Alias string
+}
+
+// Ordered list of named colors, the entries can be organized into groups
+type Palette struct {
+	Name   string
+	Colors []Entry
+}
+
+const (
+	FORMAT_GPL       = iota
+	FORMAT_ASE       = iota
+	FORMAT_ACO       = iota
+	FORMAT_PAINTNET  = iota
+	FORMAT_PROCREATE = iota
+	FORMAT_SKETCH    = iota
+	FORMAT_CSS       = iota
+	FORMAT_DTCG      = iota
+	FORMAT_STYLEDICT = iota
+)
+
+var FormatNames = []string{"gpl", "ase", "aco", "paintnet", "procreate", "sketch", "css", "dtcg", "style-dictionary"}
+
+// Binary formats are not written to terminals
+var FormatBinary = []bool{false, true, true, false, true, false, false, false, false}
+
+// Formats that can be written, the others are read only
+var FormatWritable = []bool{true, true, true, true, false, false, false, true, true}
+
+var formatExtensions = map[string]int{
+	".gpl":           FORMAT_GPL,
+	".ase":           FORMAT_ASE,
+	".aco":           FORMAT_ACO,
+	".txt":           FORMAT_PAINTNET,
+	".swatches":      FORMAT_PROCREATE,
+	".sketchpalette": FORMAT_SKETCH,
+	".css":           FORMAT_CSS,
+	".scss":          FORMAT_CSS,
+	".less":          FORMAT_CSS,
+	".tokens":        FORMAT_DTCG,
+}
+
+func ParseFormat(name string) (int, bool) {
+	name = strings.ToLower(name)
+	switch name {
+	case "paint.net", "txt":
+		return FORMAT_PAINTNET, true
+	case "scss", "less":
+		return FORMAT_CSS, true
+	case "tokens", "w3c":
+		return FORMAT_DTCG, true
+	case "styledictionary", "sd":
+		return FORMAT_STYLEDICT, true
+	}
+	for i, n := range FormatNames {
+		if n == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Format of a palette file, based on its extension
+func DetectFormat(path string) (int, bool) {
+	format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]
+	return format, ok
+}
+
+// Format of palette data, based on its first bytes
+func DetectContentFormat(data []byte) (int, bool) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("ASEF")):
+		return FORMAT_ASE, true
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return FORMAT_PROCREATE, true
+	case bytes.HasPrefix(trimmed, []byte("GIMP Palette")):
+		return FORMAT_GPL, true
+	case bytes.HasPrefix(trimmed, []byte("; paint.net")):
+		return FORMAT_PAINTNET, true
+	case bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(data, []byte(`"$value"`)):
+		return FORMAT_DTCG, true
+	case bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(data, []byte(`"compatibleVersion"`)):
+		return FORMAT_SKETCH, true
+	case bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(data, []byte(`"value"`)):
+		return FORMAT_STYLEDICT, true
+	case len(data) >= 4 && data[0] == 0 && (data[1] == 1 || data[1] == 2):
+		return FORMAT_ACO, true
+	case bytes.Contains(data, []byte("--")) || bytes.Contains(data, []byte("$")):
+		return FORMAT_CSS, true
+	}
+	return 0, false
+}
+
+// Name of the entry, or the hex value of its color if it has none
+func (e Entry) Label() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.Color.Hex()
+}
+
+func (p *Palette) Add(name string, c color.RepaColor) {
+	p.Colors = append(p.Colors, Entry{Name: name, Color: c})
+}
+
+func (p *Palette) AddToGroup(group, name string, c color.RepaColor) {
+	p.Colors = append(p.Colors, Entry{Name: name, Color: c, Group: group})
+}
+
+// Names of the groups in the order of their first entries
+func (p Palette) Groups() []string {
+	var groups []string
+	seen := map[string]bool{}
+	for _, e := range p.Colors {
+		if e.Group != "" && !seen[e.Group] {
+			seen[e.Group] = true
+			groups = append(groups, e.Group)
+		}
+	}
+	return groups
+}
+
+// Entries of the given group ("" for the ungrouped ones)
+func (p Palette) Group(name string) []Entry {
+	var entries []Entry
+	for _, e := range p.Colors {
+		if e.Group == name {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Color from HSB (HSV) coordinates, used by Photoshop and Procreate
+func hsbColor(h, s, b, a float64) color.RepaColor {
+	c := colorful.Hsv(h, s, b)
+	return color.CreateColor(color.CS_RGB, c.R, c.G, c.B, a)
+}
+
+// Write the palette in the given format
+func Write(w io.Writer, p Palette, format int) error {
+	switch format {
+	case FORMAT_GPL:
+		return WriteGPL(w, p)
+	case FORMAT_ASE:
+		return WriteASE(w, p)
+	case FORMAT_ACO:
+		return WriteACO(w, p)
+	case FORMAT_PAINTNET:
+		return WritePaintNET(w, p)
+	case FORMAT_DTCG:
+		return WriteDTCG(w, p)
+	case FORMAT_STYLEDICT:
+		return WriteStyleDictionary(w, p)
+	}
+	if format >= 0 && format < len(FormatNames) {
+		return fmt.Errorf("%s palettes cannot be written", FormatNames[format])
+	}
+	return fmt.Errorf("unknown palette format: %d", format)
+}
+
+// Read a palette in the given format
+func Read(r io.Reader, format int) (Palette, error) {
+	switch format {
+	case FORMAT_GPL:
+		return ReadGPL(r)
+	case FORMAT_ASE:
+		return ReadASE(r)
+	case FORMAT_ACO:
+		return ReadACO(r)
+	case FORMAT_PAINTNET:
+		return ReadPaintNET(r)
+	case FORMAT_PROCREATE:
+		return ReadProcreate(r)
+	case FORMAT_SKETCH:
+		return ReadSketch(r)
+	case FORMAT_CSS:
+		return ReadCSS(r)
+	case FORMAT_DTCG:
+		return ReadDTCG(r)
+	case FORMAT_STYLEDICT:
+		return ReadStyleDictionary(r)
+	}
+	return Palette{}, fmt.Errorf("unknown palette format: %d", format)
+}
+
+// Read a palette file, the format is detected from the extension or the
+// content. Palettes without a name are named after the file. The colors read
+// before an error are returned with the error.
+func ReadFile(path string) (Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	format, ok := DetectFormat(path)
+	if !ok {
+		format, ok = DetectContentFormat(data)
+	}
+	if !ok {
+		return Palette{}, fmt.Errorf("%s: unknown palette format", path)
+	}
+
+	p, err := Read(bytes.NewReader(data), format)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Name == "" {
+		base := filepath.Base(path)
+		p.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return p, err
+}
@@ -0,0 +1,367 @@
+package palette
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+func testPalette() Palette {
+	p := Palette{Name: "Test"}
+	for _, s := range []string{"red", "#336699", "rgb(0 255 0 / 50%)"} {
+		c, err := color.ParseColor(s, false)
+		if err != nil {
+			panic(err)
+		}
+		name := ""
+		if s == "red" {
+			name = "Red ✓"
+		}
+		p.Add(name, c)
+	}
+	return p
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]int{"gpl": FORMAT_GPL, "ASE": FORMAT_ASE, "aco": FORMAT_ACO, "paint.net": FORMAT_PAINTNET} {
+		if f, ok := ParseFormat(name); !ok || f != expected {
+			t.Fatalf("Wrong format for %s: %d", name, f)
+		}
+	}
+	if f, ok := DetectFormat("swatches/brand.ACO"); !ok || f != FORMAT_ACO {
+		t.Fatalf("Wrong format detected: %d", f)
+	}
+	for _, path := range []string{"brand.png", "brand.sass"} {
+		if _, ok := DetectFormat(path); ok {
+			t.Fatalf("Unknown extension should not be detected: %s", path)
+		}
+	}
+}
+
+func TestWriteGPL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testPalette(), FORMAT_GPL); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "GIMP Palette\nName: Test\n#\n255   0   0\tRed ✓\n 51 102 153\t#336699\n  0 255   0\t#00ff0080\n"
+	if buf.String() != expected {
+		t.Fatalf("Wrong GPL:\n%s", buf.String())
+	}
+}
+
+func TestWriteASE(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testPalette(), FORMAT_ASE); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	if string(data[:4]) != "ASEF" || binary.BigEndian.Uint32(data[8:]) != 3 {
+		t.Fatalf("Wrong ASE header: % x", data[:12])
+	}
+
+	// first block: "Red ✓" is 5 UTF-16 units plus the terminating zero
+	if binary.BigEndian.Uint16(data[12:]) != aseBlockColor || binary.BigEndian.Uint16(data[18:]) != 6 {
+		t.Fatalf("Wrong ASE block: % x", data[12:20])
+	}
+	blockLen := int(binary.BigEndian.Uint32(data[14:]))
+	if blockLen != 2+12+4+12+2 {
+		t.Fatalf("Wrong ASE block length: %d", blockLen)
+	}
+	if string(data[32:36]) != "RGB " {
+		t.Fatalf("Wrong ASE color model: %q", data[32:36])
+	}
+}
+
+func TestWriteACO(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testPalette(), FORMAT_ACO); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	v1 := make([]uint16, 2+3*5)
+	binary.Read(bytes.NewReader(data), binary.BigEndian, v1)
+	if v1[0] != 1 || v1[1] != 3 {
+		t.Fatalf("Wrong ACO v1 header: %v", v1[:2])
+	}
+	if v1[7] != 0 || v1[8] != 0x3333 || v1[9] != 0x6666 || v1[10] != 0x9999 {
+		t.Fatalf("Wrong ACO v1 color: %v", v1[7:12])
+	}
+
+	v2 := data[len(v1)*2:]
+	if binary.BigEndian.Uint16(v2) != 2 || binary.BigEndian.Uint16(v2[2:]) != 3 {
+		t.Fatalf("Wrong ACO v2 header: % x", v2[:4])
+	}
+	if binary.BigEndian.Uint32(v2[14:]) != 6 {
+		t.Fatalf("Wrong ACO v2 name length: % x", v2[14:18])
+	}
+
+	p := Palette{Colors: make([]Entry, 65536)}
+	buf.Reset()
+	if err := Write(&buf, p, FORMAT_ACO); err == nil || buf.Len() != 0 {
+		t.Fatalf("Too many colors should fail")
+	}
+}
+
+func TestWritePaintNET(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testPalette(), FORMAT_PAINTNET); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "\nFFFF0000\nFF336699\n8000FF00\n") {
+		t.Fatalf("Wrong Paint.NET palette:\n%s", buf.String())
+	}
+
+	p := Palette{}
+	for i := 0; i < 97; i++ {
+		p.Add("", color.BLACK)
+	}
+	if err := Write(&buf, p, FORMAT_PAINTNET); err == nil {
+		t.Fatalf("Too many colors should fail")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	p := testPalette()
+	p.Colors[1].Group = "Brand"
+	p.Colors[2].Group = "Brand"
+
+	for _, format := range []int{FORMAT_GPL, FORMAT_ASE, FORMAT_ACO, FORMAT_PAINTNET} {
+		var buf bytes.Buffer
+		if err := Write(&buf, p, format); err != nil {
+			t.Fatal(err)
+		}
+		if f, ok := DetectContentFormat(buf.Bytes()); !ok || f != format {
+			t.Fatalf("Wrong %s content format detected: %d", FormatNames[format], f)
+		}
+
+		p2, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", FormatNames[format], err)
+		}
+		if len(p2.Colors) != len(p.Colors) {
+			t.Fatalf("%s: wrong number of colors: %d", FormatNames[format], len(p2.Colors))
+		}
+		for i, e := range p2.Colors {
+			// only Paint.NET keeps the alpha
+			c := p.Colors[i].Color
+			if format != FORMAT_PAINTNET {
+				c.A = 1
+			}
+			if e.Color.Hex() != c.Hex() {
+				t.Fatalf("%s: wrong color %d: %s", FormatNames[format], i, e.Color.Hex())
+			}
+			if format != FORMAT_PAINTNET && e.Name != p.Colors[i].Label() {
+				t.Fatalf("%s: wrong name %d: %s", FormatNames[format], i, e.Name)
+			}
+		}
+
+		if format == FORMAT_ASE {
+			if groups := p2.Groups(); len(groups) != 1 || groups[0] != "Brand" || len(p2.Group("Brand")) != 2 {
+				t.Fatalf("Wrong ASE groups: %v", groups)
+			}
+		}
+	}
+}
+
+func TestReadACOSpaces(t *testing.T) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, []uint16{
+		1, 4,
+		acoSpaceHSB, 0, 0xffff, 0xffff, 0,
+		acoSpaceCMYK, 0, 0xffff, 0xffff, 0xffff,
+		acoSpaceLab, 5000, 0, 0, 0,
+		acoSpaceGray, 10000, 0, 0, 0,
+	})
+
+	p, err := ReadACO(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"#ff0000", "#00ffff", "#777777", "#000000"}
+	for i, e := range p.Colors {
+		if e.Color.Hex() != expected[i] {
+			t.Fatalf("Wrong ACO color %d: %s, expected %s", i, e.Color.Hex(), expected[i])
+		}
+	}
+}
+
+func TestReadProcreate(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("Swatches.json")
+	f.Write([]byte(`[{"name": "Sunset", "swatches": [{"hue": 0, "saturation": 1, "brightness": 1, "alpha": 1}, null, {"hue": 0.5, "saturation": 1, "brightness": 0.5}]}]`))
+	zw.Close()
+
+	if f, ok := DetectContentFormat(buf.Bytes()); !ok || f != FORMAT_PROCREATE {
+		t.Fatalf("Wrong content format detected: %d", f)
+	}
+
+	p, err := ReadProcreate(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Sunset" || len(p.Colors) != 2 || p.Colors[0].Color.Hex() != "#ff0000" || p.Colors[1].Color.Hex() != "#008080" {
+		t.Fatalf("Wrong Procreate palette: %v", p)
+	}
+}
+
+func TestReadSketch(t *testing.T) {
+	for _, src := range []string{
+		`{"compatibleVersion": "1.4", "pluginVersion": "1.4", "colors": [{"name": "Brand", "red": 0.2, "green": 0.4, "blue": 0.6, "alpha": 1}, {"red": 1, "green": 0, "blue": 0, "alpha": 0.5}]}`,
+		`{"compatibleVersion": "1.0", "pluginVersion": "1.1", "colors": ["#336699", "#ff000080"]}`,
+	} {
+		p, err := ReadSketch(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Colors) != 2 || p.Colors[0].Color.Hex() != "#336699" || p.Colors[1].Color.Hex() != "#ff000080" {
+			t.Fatalf("Wrong Sketch palette: %v", p)
+		}
+	}
+}
+
+func TestReadCSS(t *testing.T) {
+	src := `
+// brand
+$brand: #3366cc !default;
+:root {
+  --bg: #fff; /* --commented: red; */
+  --fg: oklch(from var(--bg) calc(l - 1) c h);
+  --link: $brand;
+  --space: 4px;
+  --weight: bold;
+}
+.dark {
+  --bg: #111;
+  --fg: color-mix(in srgb, var(--bg), white);
+}`
+
+	p, err := ReadCSS(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][3]string{
+		{"", "brand", "#3366cc"},
+		{"", "bg", "#ffffff"},
+		{"", "fg", "#000000"},
+		{"", "link", "#3366cc"},
+		{".dark", "bg", "#111111"},
+		{".dark", "fg", "#888888"},
+	}
+	if len(p.Colors) != len(expected) {
+		t.Fatalf("Wrong CSS palette: %v", p.Colors)
+	}
+	for i, e := range p.Colors {
+		if [3]string{e.Group, e.Name, e.Color.Hex()} != expected[i] {
+			t.Fatalf("Wrong CSS color %d: %s %s %s", i, e.Group, e.Name, e.Color.Hex())
+		}
+	}
+}
+
+func TestReadDTCG(t *testing.T) {
+	src := `{
+  "brand": {
+    "$type": "color",
+    "500": {"$value": {"colorSpace": "srgb", "components": [0.2, 0.4, 0.8], "hex": "#3366cc"}},
+    "600": {"$value": "#2952a3"},
+    "wide": {"$value": {"colorSpace": "oklch", "components": [0.7, 0.1, 200], "alpha": 0.5}},
+    "hwb": {"$value": {"colorSpace": "hwb", "components": [0, 0, 0], "hex": "#ff0000"}}
+  },
+  "space": {"$type": "dimension", "sm": {"$value": "4px"}},
+  "text": {
+    "primary": {"$value": "{brand.500}"},
+    "untyped": {"$value": "rebeccapurple"},
+    "label": {"$value": "bold"},
+    "broken": {"$type": "color", "$value": "{brand.nope}"},
+    "loop": {"$type": "color", "$value": "{text.loop}"},
+    "typo": {"$type": "color", "$value": "reed"},
+    "notcolor": {"$type": "color", "$value": "{text.label}"},
+    "dimension": {"$type": "color", "$value": "{space.sm}"}
+  }
+}`
+
+	p, err := ReadDTCG(strings.NewReader(src))
+	if err == nil || !strings.Contains(err.Error(), "text.broken: unknown alias") || !strings.Contains(err.Error(), "text.loop: circular alias") || !strings.Contains(err.Error(), "text.typo: invalid color: reed") ||
+		!strings.Contains(err.Error(), "text.notcolor: alias {text.label} is not a color") || !strings.Contains(err.Error(), "text.dimension: alias {space.sm} is not a color") {
+		t.Fatalf("Wrong errors: %v", err)
+	}
+
+	expected := [][4]string{
+		{"brand", "500", "#3366cc", ""},
+		{"brand", "600", "#2952a3", ""},
+		{"brand", "wide", "#40b1b780", ""},
+		{"brand", "hwb", "#ff0000", ""},
+		{"text", "primary", "#3366cc", "brand.500"},
+		{"text", "untyped", "#663399", ""},
+	}
+	if len(p.Colors) != len(expected) {
+		t.Fatalf("Wrong DTCG palette: %v", p.Colors)
+	}
+	for i, e := range p.Colors {
+		if [4]string{e.Group, e.Name, e.Color.Hex(), e.Alias} != expected[i] {
+			t.Fatalf("Wrong DTCG token %d: %s %s %s %s", i, e.Group, e.Name, e.Color.Hex(), e.Alias)
+		}
+	}
+}
+
+func TestWriteTokensCollision(t *testing.T) {
+	// a token and a group with the same name, in both orders
+	var tokenFirst, groupFirst Palette
+	tokenFirst.Add("brand", color.BLACK)
+	tokenFirst.AddToGroup("brand", "light", color.WHITE)
+	groupFirst.AddToGroup("brand", "light", color.WHITE)
+	groupFirst.Add("brand", color.BLACK)
+
+	for _, p := range []Palette{tokenFirst, groupFirst} {
+		for _, format := range []int{FORMAT_DTCG, FORMAT_STYLEDICT} {
+			var buf bytes.Buffer
+			if err := Write(&buf, p, format); err == nil || !strings.Contains(err.Error(), "brand: the name of a token and a group") {
+				t.Errorf("Name collision not reported: %v\n%s", err, buf.String())
+			}
+		}
+	}
+}
+
+func TestTokensRoundTrip(t *testing.T) {
+	src := `{"color": {"brand": {"value": "#3366cc", "type": "color"}, "link": {"value": "{color.brand.value}", "type": "color"}}}`
+	if f, ok := DetectContentFormat([]byte(src)); !ok || f != FORMAT_STYLEDICT {
+		t.Fatalf("Wrong content format detected: %d", f)
+	}
+
+	p, err := ReadStyleDictionary(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wide, _ := color.ParseColor("color(display-p3 0 1 0)", false)
+	p.AddToGroup("color.wide", "green", wide)
+
+	for _, format := range []int{FORMAT_DTCG, FORMAT_STYLEDICT} {
+		var buf bytes.Buffer
+		if err := Write(&buf, p, format); err != nil {
+			t.Fatal(err)
+		}
+		if f, ok := DetectContentFormat(buf.Bytes()); !ok || f != format {
+			t.Fatalf("Wrong %s content format detected: %d", FormatNames[format], f)
+		}
+
+		p2, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", FormatNames[format], err)
+		}
+		if len(p2.Colors) != 3 || p2.Colors[1].Alias != "color.brand" || p2.Colors[1].Color.Hex() != "#3366cc" {
+			t.Fatalf("%s: wrong palette: %v", FormatNames[format], p2.Colors)
+		}
+		// out of sRGB colors are kept
+		if p2.Colors[2].Group != "color.wide" || p2.Colors[2].Color.InGamut() || color.DeltaEOK(p2.Colors[2].Color, wide) > .001 {
+			t.Fatalf("%s: wrong wide gamut color: %v", FormatNames[format], p2.Colors[2])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package palette
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Procreate swatches (.swatches): a zip file with a Swatches.json, that is a
+// palette object (or a list of them) with a name and up to 30 swatches. The
+// swatches are HSB colors (0-1), empty slots are null.
+
+type procreateSwatch struct {
+	Hue        float64  `json:"hue"`
+	Saturation float64  `json:"saturation"`
+	Brightness float64  `json:"brightness"`
+	Alpha      *float64 `json:"alpha"`
+}
+
+type procreatePalette struct {
+	Name     string             `json:"name"`
+	Swatches []*procreateSwatch `json:"swatches"`
+}
+
+func ReadProcreate(r io.Reader) (Palette, error) {
+	var p Palette
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return p, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return p, errors.New("not a Procreate swatches file")
+	}
+
+	var jsonData []byte
+	for _, f := range zr.File {
+		if strings.EqualFold(f.Name, "Swatches.json") {
+			rc, err := f.Open()
+			if err != nil {
+				return p, err
+			}
+			jsonData, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return p, err
+			}
+			break
+		}
+	}
+	if jsonData == nil {
+		return p, errors.New("no Swatches.json in the Procreate swatches file")
+	}
+
+	// a single palette or a list of them
+	var palettes []procreatePalette
+	if err := json.Unmarshal(jsonData, &palettes); err != nil {
+		var single procreatePalette
+		if err := json.Unmarshal(jsonData, &single); err != nil {
+			return p, err
+		}
+		palettes = append(palettes, single)
+	}
+
+	for _, pp := range palettes {
+		if p.Name == "" {
+			p.Name = pp.Name
+		}
+		// multiple palettes are kept as groups
+		group := ""
+		if len(palettes) > 1 {
+			group = pp.Name
+		}
+		for _, s := range pp.Swatches {
+			if s == nil {
+				continue
+			}
+			alpha := 1.0
+			if s.Alpha != nil {
+				alpha = *s.Alpha
+			}
+			p.AddToGroup(group, "", hsbColor(s.Hue*360, s.Saturation, s.Brightness, alpha))
+		}
+	}
+
+	return p, nil
+}
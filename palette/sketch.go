@@ -0,0 +1,55 @@
+package palette
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Sketch palette (.sketchpalette) of the Sketch Palettes plugin: JSON with a
+// list of colors, that are hex strings (version 1) or objects with optional
+// name and red, green, blue, alpha channels (0-1, version 1.4 and later).
+
+type sketchColor struct {
+	Name  string   `json:"name"`
+	Red   float64  `json:"red"`
+	Green float64  `json:"green"`
+	Blue  float64  `json:"blue"`
+	Alpha *float64 `json:"alpha"`
+}
+
+func ReadSketch(r io.Reader) (Palette, error) {
+	var p Palette
+	var doc struct {
+		Colors []json.RawMessage `json:"colors"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return p, fmt.Errorf("not a Sketch palette: %w", err)
+	}
+
+	for i, raw := range doc.Colors {
+		var hex string
+		if err := json.Unmarshal(raw, &hex); err == nil {
+			c, err := color.ParseColor(hex, false)
+			if err != nil {
+				return p, fmt.Errorf("color %d: %w", i, err)
+			}
+			p.Add("", c)
+			continue
+		}
+
+		var sc sketchColor
+		if err := json.Unmarshal(raw, &sc); err != nil {
+			return p, fmt.Errorf("color %d: %w", i, err)
+		}
+		alpha := 1.0
+		if sc.Alpha != nil {
+			alpha = *sc.Alpha
+		}
+		p.Add(sc.Name, color.CreateColor(color.CS_RGB, sc.Red, sc.Green, sc.Blue, alpha))
+	}
+
+	return p, nil
+}
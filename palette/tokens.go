@@ -0,0 +1,454 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Design tokens: the W3C Design Tokens Community Group format (DTCG) and the
+// format of Style Dictionary
+//
+// Tokens are nested in groups, a token is an object with a $value (value in
+// Style Dictionary) and a $type (type), that can also be given by one of the
+// groups the token is in. Values can be aliases of other tokens: {group.token}
+//
+//	{
+//	  "brand": {
+//	    "$type": "color",
+//	    "500": {"$value": {"colorSpace": "srgb", "components": [0.2, 0.4, 0.8], "hex": "#3366cc"}},
+//	    "600": {"$value": "#2952a3"}
+//	  },
+//	  "link": {"$type": "color", "$value": "{brand.500}"}
+//	}
+//
+// The entries are named by the token names, and grouped by the path of their
+// groups joined with dots.
+
+// JSON object with the order of its keys
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *jsonObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: map[string]any{}}
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decode JSON keeping the order of the object keys
+func decodeJSON(dec *json.Decoder) (any, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		obj := newJSONObject()
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(kt.(string), value)
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	}
+
+	return t, nil
+}
+
+// Keys of the value and the type, "$value" and "$type" for DTCG
+type tokenDialect struct {
+	value, typ string
+}
+
+var dtcgDialect = tokenDialect{"$value", "$type"}
+var styleDictionaryDialect = tokenDialect{"value", "type"}
+
+type token struct {
+	path  []string
+	typ   string
+	value any
+}
+
+func (t token) name() string {
+	return strings.Join(t.path, ".")
+}
+
+// Tokens of the group in document order, with their (inherited) types
+func collectTokens(obj *jsonObject, path []string, typ string, dialect tokenDialect, tokens []token) []token {
+	if t, ok := obj.values[dialect.typ].(string); ok {
+		typ = t
+	}
+	if value, ok := obj.values[dialect.value]; ok && len(path) > 0 {
+		return append(tokens, token{path, typ, value})
+	}
+
+	for _, k := range obj.keys {
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+		if child, ok := obj.values[k].(*jsonObject); ok {
+			tokens = collectTokens(child, append(path[:len(path):len(path)], k), typ, dialect, tokens)
+		}
+	}
+	return tokens
+}
+
+var tokenAliasRe = regexp.MustCompile(`^\{([^{}]+)\}$`)
+
+// DTCG color spaces, the components are the coordinates of the color space
+// (see color.CreateColor) multiplied by the scales
+var dtcgColorSpaces = []struct {
+	name   string
+	mode   int
+	scales [3]float64
+}{
+	{"srgb", color.CS_RGB, [3]float64{1, 1, 1}},
+	{"srgb-linear", color.CS_LINEARRGB, [3]float64{1, 1, 1}},
+	{"hsl", color.CS_HSL, [3]float64{1, 100, 100}},
+	{"lab", color.CS_LAB, [3]float64{100, 100, 100}},
+	{"lch", color.CS_LCH, [3]float64{100, 100, 1}},
+	{"oklab", color.CS_OKLAB, [3]float64{1, 1, 1}},
+	{"oklch", color.CS_OKLCH, [3]float64{1, 1, 1}},
+	{"xyz-d65", color.CS_XYZ, [3]float64{1, 1, 1}},
+	{"display-p3", color.CS_DISPLAYP3, [3]float64{1, 1, 1}},
+	{"rec2020", color.CS_REC2020, [3]float64{1, 1, 1}},
+	{"a98-rgb", color.CS_A98RGB, [3]float64{1, 1, 1}},
+	{"prophoto-rgb", color.CS_PROPHOTO, [3]float64{1, 1, 1}},
+}
+
+func jsonNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		// missing components
+		return 0, n == "none"
+	}
+	return 0, false
+}
+
+// Color of a DTCG color object: {colorSpace, components, alpha, hex}
+func dtcgColor(obj *jsonObject) (color.RepaColor, error) {
+	space, _ := obj.values["colorSpace"].(string)
+	components, _ := obj.values["components"].([]any)
+
+	alpha := 1.0
+	if a, ok := obj.values["alpha"]; ok {
+		if alpha, ok = jsonNumber(a); !ok {
+			return color.NOCOLOR, errors.New("invalid alpha")
+		}
+	}
+
+	for _, cs := range dtcgColorSpaces {
+		if cs.name != space {
+			continue
+		}
+		if len(components) != 3 {
+			return color.NOCOLOR, errors.New("3 components are required")
+		}
+		var v [3]float64
+		for i, comp := range components {
+			n, ok := jsonNumber(comp)
+			if !ok {
+				return color.NOCOLOR, fmt.Errorf("invalid component: %v", comp)
+			}
+			v[i] = n / cs.scales[i]
+		}
+		return color.CreateColor(cs.mode, v[0], v[1], v[2], alpha), nil
+	}
+
+	// unsupported color spaces (hwb, xyz-d50) fall back to the hex value
+	if hex, ok := obj.values["hex"].(string); ok {
+		c, err := color.ParseColor(hex, false)
+		c.A = alpha
+		return c, err
+	}
+	return color.NOCOLOR, fmt.Errorf("unsupported color space: %q", space)
+}
+
+type tokenResolver struct {
+	dialect tokenDialect
+	tokens  map[string]token
+}
+
+// Color and type of a token, following the aliases
+func (tr tokenResolver) resolve(t token, seen map[string]bool) (color.RepaColor, string, error) {
+	if seen[t.name()] {
+		return color.NOCOLOR, t.typ, errors.New("circular alias")
+	}
+	seen[t.name()] = true
+
+	if alias, ok := tr.alias(t); ok {
+		target, ok := tr.tokens[alias]
+		if !ok {
+			return color.NOCOLOR, t.typ, fmt.Errorf("unknown alias: {%s}", alias)
+		}
+		c, typ, err := tr.resolve(target, seen)
+		if err == nil && t.typ == "color" && typ != "color" {
+			return color.NOCOLOR, t.typ, fmt.Errorf("alias {%s} is not a color", alias)
+		}
+		if t.typ != "" {
+			typ = t.typ
+		}
+		return c, typ, err
+	}
+
+	if t.typ != "" && t.typ != "color" {
+		return color.NOCOLOR, t.typ, nil
+	}
+
+	switch v := t.value.(type) {
+	case string:
+		if t.typ == "" {
+			// only CSS colors are taken as untyped colors
+			info, err := color.ParseColorInfo(v, nil, false)
+			if err != nil || info.Source != color.SOURCE_CSS {
+				return color.NOCOLOR, "", nil
+			}
+			return info.Color, "color", nil
+		}
+		info, err := color.ParseColorInfo(v, nil, false)
+		if err != nil || info.Source != color.SOURCE_CSS {
+			return color.NOCOLOR, t.typ, fmt.Errorf("invalid color: %s", v)
+		}
+		return info.Color, t.typ, nil
+	case *jsonObject:
+		if _, ok := v.values["colorSpace"]; !ok && t.typ == "" {
+			return color.NOCOLOR, "", nil
+		}
+		c, err := dtcgColor(v)
+		return c, "color", err
+	}
+
+	if t.typ == "color" {
+		return color.NOCOLOR, t.typ, fmt.Errorf("invalid color: %v", t.value)
+	}
+	return color.NOCOLOR, t.typ, nil
+}
+
+// Path of the token the value of the token refers to
+func (tr tokenResolver) alias(t token) (string, bool) {
+	s, ok := t.value.(string)
+	if !ok {
+		return "", false
+	}
+	m := tokenAliasRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", false
+	}
+	// Style Dictionary aliases can end with .value
+	return strings.TrimSuffix(m[1], "."+tr.dialect.value), true
+}
+
+func readTokens(r io.Reader, dialect tokenDialect) (Palette, error) {
+	var p Palette
+
+	root, err := decodeJSON(json.NewDecoder(r))
+	if err != nil {
+		return p, err
+	}
+	obj, ok := root.(*jsonObject)
+	if !ok {
+		return p, errors.New("tokens should be a JSON object")
+	}
+
+	tokens := collectTokens(obj, nil, "", dialect, nil)
+	tr := tokenResolver{dialect, map[string]token{}}
+	for _, t := range tokens {
+		tr.tokens[t.name()] = t
+	}
+
+	var errs []error
+	for _, t := range tokens {
+		c, typ, err := tr.resolve(t, map[string]bool{})
+		if typ != "color" {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.name(), err))
+			continue
+		}
+
+		alias, _ := tr.alias(t)
+		p.Colors = append(p.Colors, Entry{
+			Name:  t.path[len(t.path)-1],
+			Color: c,
+			Group: strings.Join(t.path[:len(t.path)-1], "."),
+			Alias: alias,
+		})
+	}
+
+	// the valid colors are returned with the errors of the invalid ones
+	return p, errors.Join(errs...)
+}
+
+// Read DTCG tokens, invalid color tokens are reported in the error, and are
+// left out of the palette
+func ReadDTCG(r io.Reader) (Palette, error) {
+	return readTokens(r, dtcgDialect)
+}
+
+// Read Style Dictionary tokens, see ReadDTCG
+func ReadStyleDictionary(r io.Reader) (Palette, error) {
+	return readTokens(r, styleDictionaryDialect)
+}
+
+func round4(v float64) float64 {
+	v = math.Round(v*10000) / 10000
+	if v == 0 {
+		return 0
+	}
+	return v
+}
+
+// Token path of an entry, dots are not allowed in the names
+func tokenPath(e Entry) []string {
+	var path []string
+	for _, g := range strings.Split(e.Group, ".") {
+		if g = strings.TrimSpace(g); g != "" {
+			path = append(path, g)
+		}
+	}
+	return append(path, strings.ReplaceAll(e.Label(), ".", "-"))
+}
+
+func dtcgValue(c color.RepaColor) *jsonObject {
+	space := dtcgColorSpaces[0]
+	for _, cs := range dtcgColorSpaces {
+		if cs.mode == c.Space && c.Space != color.CS_RGB {
+			space = cs
+		}
+	}
+
+	v1, v2, v3 := c.Coordinates(space.mode)
+	if space.mode == color.CS_RGB {
+		// the clipped or mapped sRGB color
+		g := c.ToGamut()
+		v1, v2, v3 = g.R, g.G, g.B
+	}
+
+	value := newJSONObject()
+	value.set("colorSpace", space.name)
+	value.set("components", []float64{round4(v1 * space.scales[0]), round4(v2 * space.scales[1]), round4(v3 * space.scales[2])})
+	if c.A < 1 {
+		value.set("alpha", round4(c.A))
+	}
+	opaque := c
+	opaque.A = 1
+	value.set("hex", opaque.Hex())
+	return value
+}
+
+// Style Dictionary values are hex colors, or oklch() if they are out of the
+// sRGB gamut
+func styleDictionaryValue(c color.RepaColor) string {
+	if c.InGamut() {
+		return c.Hex()
+	}
+	return c.OkLchString()
+}
+
+func writeTokens(w io.Writer, p Palette, dialect tokenDialect) error {
+	root := newJSONObject()
+	// token nodes, the other objects are groups
+	tokens := map[*jsonObject]bool{}
+
+	for _, e := range p.Colors {
+		path := tokenPath(e)
+		group := root
+		for i, name := range path[:len(path)-1] {
+			child, ok := group.values[name].(*jsonObject)
+			if !ok {
+				child = newJSONObject()
+				group.set(name, child)
+			} else if tokens[child] {
+				return fmt.Errorf("%s: the name of a token and a group", strings.Join(path[:i+1], "."))
+			}
+			group = child
+		}
+		if existing, ok := group.values[path[len(path)-1]].(*jsonObject); ok && !tokens[existing] {
+			return fmt.Errorf("%s: the name of a token and a group", strings.Join(path, "."))
+		}
+
+		t := newJSONObject()
+		t.set(dialect.typ, "color")
+		switch {
+		case e.Alias != "":
+			t.set(dialect.value, "{"+e.Alias+"}")
+		case dialect == dtcgDialect:
+			t.set(dialect.value, dtcgValue(e.Color))
+		default:
+			t.set(dialect.value, styleDictionaryValue(e.Color))
+		}
+		group.set(path[len(path)-1], t)
+		tokens[t] = true
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func WriteDTCG(w io.Writer, p Palette) error {
+	return writeTokens(w, p, dtcgDialect)
+}
+
+func WriteStyleDictionary(w io.Writer, p Palette) error {
+	return writeTokens(w, p, styleDictionaryDialect)
+}
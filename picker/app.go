@@ -26,6 +26,13 @@ import (
 const SLIDER_LGAP = 4
 const SLIDER_RGAP = 1
 
+// Color vision deficiencies to simulate below the picked color
+type CvdOptions struct {
+	Kinds    []int
+	Method   int
+	Severity float64
+}
+
 type model struct {
 	components []string
 	values     []float64
@@ -34,6 +41,7 @@ type model struct {
 	width      int
 	height     int
 	step	   float64
+	cvd        CvdOptions
 }
 
 func getSliderWidth(width int) int {
@@ -62,7 +70,7 @@ func mouseWheel(y int, change float64, m model) model {
 }
 
 
-func initialModel(c color.RepaColor, showAlpha bool) model {
+func initialModel(c color.RepaColor, showAlpha bool, cvd CvdOptions) model {
 	components := []string{
 		"red",
 		"green",
@@ -76,6 +84,7 @@ func initialModel(c color.RepaColor, showAlpha bool) model {
 	return model{
 		components: components,
 		values: []float64{c.R, c.G, c.B, c.A},
+		cvd: cvd,
 	}
 }
 
@@ -190,6 +199,10 @@ func (m model) View() string {
 		textrepr := "\n" + display.TextColorDetails(m.color)
 
 		s += display.MergeStringsVertically(ansirepr, textrepr, 24)
+
+		if len(m.cvd.Kinds) > 0 && m.height >= 16 + len(m.components) + 4 {
+			s += "\n" + display.CvdSwatches(m.color, m.cvd.Kinds, m.cvd.Severity, m.cvd.Method) + "\n"
+		}
 	} else if m.height >= 5 {
 		s += "\n" + m.color.AnsiBg() + m.color.A11YPair().AnsiFg() + m.color.Hex() + color.ANSI_RESET + "\n"
 	}
@@ -197,16 +210,16 @@ func (m model) View() string {
 	return s
 }
 
-func RunPicker(c color.RepaColor, showAlpha bool) {
-	p := tea.NewProgram(initialModel(c, showAlpha), tea.WithMouseAllMotion(), tea.WithAltScreen())
+// Run the picker and return the picked color
+func RunPicker(c color.RepaColor, showAlpha bool, cvd CvdOptions) color.RepaColor {
+	p := tea.NewProgram(initialModel(c, showAlpha, cvd), tea.WithMouseAllMotion(), tea.WithAltScreen())
 	m, err := p.Run()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error starting program: %v", err)
 		os.Exit(1)
 	}
 
-	// TODO display it as requested (hex by default)
-	fmt.Println(m.(model).color.Hex())
+	return m.(model).color
 }
 
 func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
@@ -221,7 +234,7 @@ func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 		}
 	}
 
-	return initialModel(c, false), []tea.ProgramOption{tea.WithMouseAllMotion(), tea.WithAltScreen()}
+	return initialModel(c, false, CvdOptions{}), []tea.ProgramOption{tea.WithMouseAllMotion(), tea.WithAltScreen()}
 }
 
 func ServePicker(port string) {
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Alacritty (TOML), to be imported from alacritty.toml:
+//
+//	[general]
+//	import = ["~/.config/alacritty/themes/<name>.toml"]
+func WriteAlacritty(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+
+	if t.Name != "" {
+		fmt.Fprintf(bw, "# %s\n\n", t.Name)
+	}
+	fmt.Fprintf(bw, "[colors.primary]\nbackground = \"%s\"\nforeground = \"%s\"\n\n", hex(t.Background), hex(t.Foreground))
+	fmt.Fprintf(bw, "[colors.cursor]\ntext = \"%s\"\ncursor = \"%s\"\n\n", hex(t.CursorText), hex(t.Cursor))
+	fmt.Fprintf(bw, "[colors.selection]\ntext = \"%s\"\nbackground = \"%s\"\n", hex(t.SelectionForeground), hex(t.SelectionBackground))
+	for i, section := range []string{"normal", "bright"} {
+		fmt.Fprintf(bw, "\n[colors.%s]\n", section)
+		for j, name := range ANSINames {
+			fmt.Fprintf(bw, "%s = \"%s\"\n", name, hex(t.ANSI[i*8+j]))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Alacritty theme, TOML or the YAML of older versions (alacritty.yml)
+func ReadAlacritty(data []byte) (Theme, error) {
+	var t Theme
+	var root map[string]any
+	var err error
+	if bytes.Contains(data, []byte("[colors")) || !bytes.Contains(data, []byte("colors:")) {
+		root, err = parseTOML(data)
+	} else {
+		err = yaml.Unmarshal(data, &root)
+	}
+	if err != nil {
+		return t, err
+	}
+
+	names := []struct{ section, key, name string }{
+		{"primary", "background", "background"},
+		{"primary", "foreground", "foreground"},
+		{"cursor", "cursor", "cursor"},
+		{"cursor", "text", "cursor-text"},
+		{"selection", "background", "selection-background"},
+		{"selection", "text", "selection-foreground"},
+	}
+	for i := range t.ANSI {
+		section := "normal"
+		if i >= 8 {
+			section = "bright"
+		}
+		names = append(names, struct{ section, key, name string }{section, ANSINames[i%8], ANSIName(i)})
+	}
+
+	for _, n := range names {
+		v, ok := lookup(root, "colors", n.section, n.key)
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return t, fmt.Errorf("colors.%s.%s: invalid color: %v", n.section, n.key, v)
+		}
+		if isColorKeyword(s) {
+			continue
+		}
+		c, err := parseColor(s)
+		if err != nil {
+			return t, fmt.Errorf("colors.%s.%s: %w", n.section, n.key, err)
+		}
+		t.setColor(n.name, c)
+	}
+
+	return t, nil
+}
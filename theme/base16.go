@@ -0,0 +1,156 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// base16 and base24 schemes (YAML), both the original format
+//
+//	scheme: "Name"
+//	base00: "181818"
+//
+// and the tinted-theming format with the colors in a palette map. The ANSI
+// colors are mapped like the base16-shell and base24 terminal templates do.
+
+var Base16Names = []string{
+	"base00", "base01", "base02", "base03", "base04", "base05", "base06", "base07",
+	"base08", "base09", "base0A", "base0B", "base0C", "base0D", "base0E", "base0F",
+}
+
+// Base colors of the ANSI colors, the bright ones are only used by base24
+var base16ANSI = [16]string{
+	"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05",
+	"base03", "base12", "base14", "base13", "base16", "base17", "base15", "base07",
+}
+
+// base16 bright colors are the same as the normal ones
+var base16Bright = map[string]string{
+	"base12": "base08",
+	"base14": "base0B",
+	"base13": "base0A",
+	"base16": "base0D",
+	"base17": "base0E",
+	"base15": "base0C",
+}
+
+func ReadBase16(data []byte) (Theme, error) {
+	var t Theme
+	// the raw scalars, so unquoted hex values (999999, 000000) are kept as
+	// they are written
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return t, err
+	}
+
+	colors := doc
+	if palette, ok := doc["palette"]; ok && palette.Kind == yaml.MappingNode {
+		colors = nil
+		if err := palette.Decode(&colors); err != nil {
+			return t, err
+		}
+	}
+	if name, ok := doc["name"]; ok && name.Kind == yaml.ScalarNode {
+		t.Name = name.Value
+	} else if name, ok := doc["scheme"]; ok && name.Kind == yaml.ScalarNode {
+		t.Name = name.Value
+	}
+	if _, ok := colors["base00"]; !ok {
+		return t, errors.New("not a base16 scheme")
+	}
+
+	get := func(base string) (color.RepaColor, error) {
+		v, ok := colors[base]
+		if !ok {
+			if fallback, ok := base16Bright[base]; ok {
+				v = colors[fallback]
+			}
+		}
+		if v.Kind != yaml.ScalarNode || v.Value == "" {
+			return color.NOCOLOR, fmt.Errorf("%s: missing color", base)
+		}
+		c, err := parseColor(v.Value)
+		if err != nil {
+			return c, fmt.Errorf("%s: %w", base, err)
+		}
+		return c, nil
+	}
+
+	for i, base := range Base16Names {
+		c, err := get(base)
+		if err != nil {
+			return t, err
+		}
+		t.Base16[i] = c
+	}
+	for i, base := range base16ANSI {
+		c, err := get(base)
+		if err != nil {
+			return t, err
+		}
+		t.ANSI[i] = c
+	}
+	t.Background = t.Base16[0x0]
+	t.Foreground = t.Base16[0x5]
+	t.Cursor = t.Foreground
+	t.CursorText = t.Background
+	t.SelectionBackground = t.Base16[0x2]
+	t.SelectionForeground = t.Foreground
+
+	return t, nil
+}
+
+// The base16 colors of the theme, used by the editor formats: the colors of
+// the base16 scheme it was read from, or derived from the terminal colors.
+//
+//	base00-07  background to foreground shades (base02: selection, base03: comments)
+//	base08-0F  red, orange, yellow, green, cyan, blue, magenta, brown
+func (t Theme) EditorColors() [16]color.RepaColor {
+	if t.Base16[0] != color.NOCOLOR {
+		return t.Base16
+	}
+
+	mix := func(c1, c2 color.RepaColor, p float64) color.RepaColor {
+		return color.Interpolate(c1, c2, p, color.CS_OKLAB, color.HUE_SHORTER).GamutMap()
+	}
+	// base06 and base07 are further from the background than the foreground
+	l, c, h := t.Foreground.Coordinates(color.CS_OKLCH)
+	dir := .05
+	if !t.Dark() {
+		dir = -dir
+	}
+	extreme := func(steps float64) color.RepaColor {
+		return color.CreateColor(color.CS_OKLCH, math.Max(0, math.Min(1, l+steps*dir)), c, h, 1).GamutMap()
+	}
+
+	selection := t.SelectionBackground
+	if selection == t.Foreground {
+		// reverse video
+		selection = mix(t.Background, t.Foreground, .2)
+	}
+
+	a := t.ANSI
+	return [16]color.RepaColor{
+		t.Background,
+		mix(t.Background, t.Foreground, .08),
+		selection,
+		a[8],
+		mix(a[8], t.Foreground, .5),
+		t.Foreground,
+		extreme(1),
+		extreme(2),
+		a[1],
+		color.Interpolate(a[1], a[3], .5, color.CS_OKLCH, color.HUE_SHORTER).GamutMap(),
+		a[3],
+		a[2],
+		a[6],
+		a[4],
+		a[5],
+		mix(a[1], t.Background, .35),
+	}
+}
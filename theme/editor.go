@@ -0,0 +1,179 @@
+package theme
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Editor color schemes (Neovim, Vim, VS Code, Helix) are written from the
+// base16 colors of the theme (see EditorColors), with the usual base16
+// assignments: base03 for comments, base08 for variables, base09 for
+// constants, base0A for types, base0B for strings, base0C for escapes,
+// base0D for functions and base0E for keywords.
+
+const (
+	BASE00 = iota
+	BASE01 = iota
+	BASE02 = iota
+	BASE03 = iota
+	BASE04 = iota
+	BASE05 = iota
+	BASE06 = iota
+	BASE07 = iota
+	BASE08 = iota
+	BASE09 = iota
+	BASE0A = iota
+	BASE0B = iota
+	BASE0C = iota
+	BASE0D = iota
+	BASE0E = iota
+	BASE0F = iota
+	// no color, the default is used
+	BASE_NONE = -1
+)
+
+// Vim highlight group
+type highlightGroup struct {
+	name   string
+	fg, bg int
+	// comma separated attributes (bold, italic, underline, undercurl, reverse)
+	style string
+}
+
+var vimHighlightGroups = []highlightGroup{
+	// editor
+	{"Normal", BASE05, BASE00, ""},
+	{"NormalFloat", BASE05, BASE01, ""},
+	{"FloatBorder", BASE03, BASE01, ""},
+	{"Cursor", BASE00, BASE05, ""},
+	{"CursorLine", BASE_NONE, BASE01, ""},
+	{"CursorColumn", BASE_NONE, BASE01, ""},
+	{"ColorColumn", BASE_NONE, BASE01, ""},
+	{"CursorLineNr", BASE04, BASE01, "bold"},
+	{"LineNr", BASE03, BASE00, ""},
+	{"SignColumn", BASE03, BASE00, ""},
+	{"Visual", BASE_NONE, BASE02, ""},
+	{"Search", BASE01, BASE0A, ""},
+	{"IncSearch", BASE01, BASE09, ""},
+	{"MatchParen", BASE_NONE, BASE03, ""},
+	{"StatusLine", BASE04, BASE02, ""},
+	{"StatusLineNC", BASE03, BASE01, ""},
+	{"VertSplit", BASE02, BASE00, ""},
+	{"WinSeparator", BASE02, BASE00, ""},
+	{"TabLine", BASE03, BASE01, ""},
+	{"TabLineSel", BASE0B, BASE01, ""},
+	{"TabLineFill", BASE03, BASE01, ""},
+	{"Pmenu", BASE05, BASE01, ""},
+	{"PmenuSel", BASE01, BASE05, ""},
+	{"Folded", BASE03, BASE01, ""},
+	{"FoldColumn", BASE0C, BASE00, ""},
+	{"NonText", BASE03, BASE_NONE, ""},
+	{"SpecialKey", BASE03, BASE_NONE, ""},
+	{"Whitespace", BASE02, BASE_NONE, ""},
+	{"Directory", BASE0D, BASE_NONE, ""},
+	{"Title", BASE0D, BASE_NONE, "bold"},
+	{"Question", BASE0D, BASE_NONE, ""},
+	{"MoreMsg", BASE0B, BASE_NONE, ""},
+	{"ModeMsg", BASE0B, BASE_NONE, ""},
+	{"ErrorMsg", BASE08, BASE00, ""},
+	{"WarningMsg", BASE08, BASE_NONE, ""},
+	{"WildMenu", BASE08, BASE0A, ""},
+	// syntax
+	{"Comment", BASE03, BASE_NONE, "italic"},
+	{"Constant", BASE09, BASE_NONE, ""},
+	{"String", BASE0B, BASE_NONE, ""},
+	{"Character", BASE08, BASE_NONE, ""},
+	{"Number", BASE09, BASE_NONE, ""},
+	{"Boolean", BASE09, BASE_NONE, ""},
+	{"Float", BASE09, BASE_NONE, ""},
+	{"Identifier", BASE08, BASE_NONE, ""},
+	{"Function", BASE0D, BASE_NONE, ""},
+	{"Statement", BASE08, BASE_NONE, ""},
+	{"Conditional", BASE0E, BASE_NONE, ""},
+	{"Repeat", BASE0A, BASE_NONE, ""},
+	{"Label", BASE0A, BASE_NONE, ""},
+	{"Operator", BASE05, BASE_NONE, ""},
+	{"Keyword", BASE0E, BASE_NONE, ""},
+	{"Exception", BASE08, BASE_NONE, ""},
+	{"PreProc", BASE0A, BASE_NONE, ""},
+	{"Include", BASE0D, BASE_NONE, ""},
+	{"Define", BASE0E, BASE_NONE, ""},
+	{"Macro", BASE08, BASE_NONE, ""},
+	{"Type", BASE0A, BASE_NONE, ""},
+	{"StorageClass", BASE0A, BASE_NONE, ""},
+	{"Structure", BASE0E, BASE_NONE, ""},
+	{"Typedef", BASE0A, BASE_NONE, ""},
+	{"Special", BASE0C, BASE_NONE, ""},
+	{"SpecialChar", BASE0F, BASE_NONE, ""},
+	{"Tag", BASE0A, BASE_NONE, ""},
+	{"Delimiter", BASE0F, BASE_NONE, ""},
+	{"SpecialComment", BASE0C, BASE_NONE, ""},
+	{"Debug", BASE08, BASE_NONE, ""},
+	{"Underlined", BASE08, BASE_NONE, "underline"},
+	{"Error", BASE00, BASE08, ""},
+	{"Todo", BASE0A, BASE01, ""},
+	// diff
+	{"DiffAdd", BASE0B, BASE01, ""},
+	{"DiffChange", BASE03, BASE01, ""},
+	{"DiffDelete", BASE08, BASE01, ""},
+	{"DiffText", BASE0D, BASE01, ""},
+	{"diffAdded", BASE0B, BASE_NONE, ""},
+	{"diffRemoved", BASE08, BASE_NONE, ""},
+	{"diffLine", BASE0C, BASE_NONE, ""},
+	// spelling
+	{"SpellBad", BASE_NONE, BASE_NONE, "undercurl"},
+	{"SpellCap", BASE_NONE, BASE_NONE, "undercurl"},
+}
+
+// Neovim only groups
+var neovimHighlightGroups = []highlightGroup{
+	{"DiagnosticError", BASE08, BASE_NONE, ""},
+	{"DiagnosticWarn", BASE0A, BASE_NONE, ""},
+	{"DiagnosticInfo", BASE0D, BASE_NONE, ""},
+	{"DiagnosticHint", BASE0C, BASE_NONE, ""},
+	{"DiagnosticUnderlineError", BASE_NONE, BASE_NONE, "undercurl"},
+	{"DiagnosticUnderlineWarn", BASE_NONE, BASE_NONE, "undercurl"},
+	{"DiagnosticUnderlineInfo", BASE_NONE, BASE_NONE, "undercurl"},
+	{"DiagnosticUnderlineHint", BASE_NONE, BASE_NONE, "undercurl"},
+	{"LspReferenceText", BASE_NONE, BASE02, ""},
+	{"LspReferenceRead", BASE_NONE, BASE02, ""},
+	{"LspReferenceWrite", BASE_NONE, BASE02, ""},
+	{"@variable", BASE05, BASE_NONE, ""},
+	{"@property", BASE08, BASE_NONE, ""},
+	{"@constructor", BASE0C, BASE_NONE, ""},
+	{"@markup.heading", BASE0D, BASE_NONE, "bold"},
+	{"@markup.link.url", BASE09, BASE_NONE, "underline"},
+}
+
+// Special color of the undercurl groups (the color of the squiggly line)
+var undercurlColors = map[string]int{
+	"SpellBad":                 BASE08,
+	"SpellCap":                 BASE0D,
+	"DiagnosticUnderlineError": BASE08,
+	"DiagnosticUnderlineWarn":  BASE0A,
+	"DiagnosticUnderlineInfo":  BASE0D,
+	"DiagnosticUnderlineHint":  BASE0C,
+}
+
+// Name of the color scheme, usable as a file name
+func schemeName(t Theme) string {
+	name := strings.ToLower(strings.TrimSpace(t.Name))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name)
+	if name == "" {
+		return "repacolor"
+	}
+	return name
+}
+
+// "dark" or "light"
+func background(t Theme) string {
+	if t.Dark() {
+		return "dark"
+	}
+	return "light"
+}
@@ -0,0 +1,100 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// foot (foot.ini syntax, colors without the #), to be included from foot.ini:
+//
+//	[main]
+//	include=~/.config/foot/themes/<name>.ini
+func WriteFoot(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+	h := func(c color.RepaColor) string { return strings.TrimPrefix(hex(c), "#") }
+
+	if t.Name != "" {
+		fmt.Fprintf(bw, "# %s\n\n", t.Name)
+	}
+	// text color, then cursor color
+	fmt.Fprintf(bw, "[cursor]\ncolor=%s %s\n\n", h(t.CursorText), h(t.Cursor))
+	fmt.Fprintf(bw, "[colors]\nbackground=%s\nforeground=%s\n", h(t.Background), h(t.Foreground))
+	fmt.Fprintf(bw, "selection-background=%s\nselection-foreground=%s\n", h(t.SelectionBackground), h(t.SelectionForeground))
+	for i, c := range t.ANSI {
+		section := "regular"
+		if i >= 8 {
+			section = "bright"
+		}
+		fmt.Fprintf(bw, "%s%d=%s\n", section, i%8, h(c))
+	}
+
+	return bw.Flush()
+}
+
+// foot theme, the [colors] and [cursor] sections of foot.ini
+func ReadFoot(data []byte) (Theme, error) {
+	var t Theme
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	section := ""
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		var err error
+		set := func(name, value string) {
+			if err != nil {
+				return
+			}
+			var c color.RepaColor
+			if c, err = parseColor(value); err == nil {
+				t.setColor(name, c)
+			}
+		}
+
+		switch {
+		case section == "cursor" && key == "color", section == "colors" && key == "cursor":
+			// text color, then cursor color
+			if colors := strings.Fields(value); len(colors) == 2 {
+				set("cursor-text", colors[0])
+				set("cursor", colors[1])
+			}
+		case section != "colors":
+		case key == "background", key == "foreground", key == "selection-background", key == "selection-foreground":
+			set(key, value)
+		case strings.HasPrefix(key, "regular"), strings.HasPrefix(key, "bright"):
+			i, e := strconv.Atoi(strings.TrimLeft(key, "abcdefghijklmnopqrstuvwxyz"))
+			if e != nil || i < 0 || i > 7 {
+				continue
+			}
+			if strings.HasPrefix(key, "bright") {
+				i += 8
+			}
+			set(ANSIName(i), value)
+		}
+		if err != nil {
+			return t, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+
+	return t, scanner.Err()
+}
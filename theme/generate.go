@@ -0,0 +1,172 @@
+package theme
+
+import (
+	"math"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Seeds of a generated theme
+type Options struct {
+	Name string
+	// derived from the first accent if not given (NOCOLOR)
+	Background color.RepaColor
+	// derived from the background if not given (NOCOLOR)
+	Foreground color.RepaColor
+	// each one replaces the ANSI color with the closest hue (only its
+	// lightness is changed if it doesn't have the minimal contrast), the
+	// first one is also used for the cursor and the selection
+	Accents []color.RepaColor
+	// minimal WCAG 2 contrast ratio of the text colors on the background
+	// (4.5 if not given)
+	MinContrast float64
+}
+
+// OKLCH hues of red, green, yellow, blue, magenta and cyan
+var ansiHues = [6]float64{25, 145, 95, 255, 330, 200}
+
+const (
+	defaultChroma = .13
+	// accents with lower chroma are not matched to the ANSI colors
+	accentMinChroma = .03
+	// contrast ratio of bright black, that is used for dimmed text
+	dimContrast = 3
+)
+
+func oklch(l, c, h float64) color.RepaColor {
+	return color.CreateColor(color.CS_OKLCH, math.Max(0, math.Min(1, l)), c, h, 1).GamutMap()
+}
+
+func hueDistance(h1, h2 float64) float64 {
+	d := math.Abs(math.Mod(h1-h2, 360))
+	return math.Min(d, 360-d)
+}
+
+// The color, or the closest one with the same hue that has the target
+// contrast on the background
+func ensureContrast(c, bg color.RepaColor, target float64) color.RepaColor {
+	if c.ContrastRatio(bg) >= target {
+		return c
+	}
+	fixed, _ := c.FixContrast(bg, target, color.CONTRAST_WCAG2)
+	return fixed
+}
+
+// Generate a theme from the seeds: the ANSI colors get evenly bright colors
+// with the usual hues (or the accents), tinted grays, and every text color
+// reaches the minimal contrast on the background
+func Generate(opts Options) Theme {
+	minContrast := opts.MinContrast
+	if minContrast <= 0 {
+		minContrast = 4.5
+	}
+
+	var accents []color.RepaColor
+	for _, a := range opts.Accents {
+		a.A = 1
+		accents = append(accents, a)
+	}
+
+	bg := opts.Background
+	if bg == color.NOCOLOR {
+		hue := 260.0
+		if len(accents) > 0 {
+			_, _, hue = accents[0].Coordinates(color.CS_OKLCH)
+		}
+		bg = oklch(.2, .015, hue)
+	}
+	bg.A = 1
+	bgL, bgC, bgH := bg.Coordinates(color.CS_OKLCH)
+	dark := bgL < .6
+	grayC := math.Min(bgC, .02)
+
+	fg := opts.Foreground
+	if fg == color.NOCOLOR {
+		fg = oklch(.9, grayC, bgH)
+		if !dark {
+			fg = oklch(.3, grayC, bgH)
+		}
+	}
+	fg.A = 1
+	fg = ensureContrast(fg, bg, minContrast)
+	fgL, _, fgH := fg.Coordinates(color.CS_OKLCH)
+
+	t := Theme{Name: opts.Name, Background: bg, Foreground: fg}
+
+	// chromatic colors
+	normalL, brightL := .7, .8
+	if !dark {
+		normalL, brightL = .5, .42
+	}
+	chroma := defaultChroma
+	var chromatic []color.RepaColor
+	for _, a := range accents {
+		if _, c, _ := a.Coordinates(color.CS_OKLCH); c >= accentMinChroma {
+			chromatic = append(chromatic, a)
+		}
+	}
+	if len(chromatic) > 0 {
+		chroma = 0
+		for _, a := range chromatic {
+			_, c, _ := a.Coordinates(color.CS_OKLCH)
+			chroma += c / float64(len(chromatic))
+		}
+	}
+
+	for i, h := range ansiHues {
+		t.ANSI[i+1] = oklch(normalL, chroma, h)
+		t.ANSI[i+9] = oklch(brightL, chroma*1.1, h)
+	}
+	assigned := [6]bool{}
+	for _, a := range chromatic {
+		l, c, h := a.Coordinates(color.CS_OKLCH)
+		slot := -1
+		for i, ah := range ansiHues {
+			if !assigned[i] && (slot < 0 || hueDistance(h, ah) < hueDistance(h, ansiHues[slot])) {
+				slot = i
+			}
+		}
+		if slot < 0 {
+			break
+		}
+		assigned[slot] = true
+		t.ANSI[slot+1] = a
+		t.ANSI[slot+9] = oklch(l+brightL-normalL, c*1.1, h)
+	}
+
+	// grays, black is the color closer to the background in dark themes,
+	// white in light themes
+	if dark {
+		t.ANSI[0] = oklch(bgL+.08, grayC, bgH)
+		t.ANSI[7] = oklch(fgL-.08, grayC, fgH)
+		t.ANSI[15] = oklch(fgL+.05, grayC, fgH)
+	} else {
+		t.ANSI[0] = oklch(fgL, grayC, fgH)
+		t.ANSI[7] = oklch(bgL-.12, grayC, bgH)
+		t.ANSI[15] = oklch(bgL-.05, grayC, bgH)
+	}
+	t.ANSI[8] = oklch(.55, grayC, bgH)
+
+	for i := range t.ANSI {
+		switch {
+		case i == 8:
+			t.ANSI[i] = ensureContrast(t.ANSI[i], bg, dimContrast)
+		case t.IsBackgroundColor(i):
+			// not used for text
+		default:
+			t.ANSI[i] = ensureContrast(t.ANSI[i], bg, minContrast)
+		}
+	}
+
+	// cursor and selection
+	highlight := fg
+	if len(accents) > 0 {
+		highlight = accents[0]
+	}
+	t.Cursor = ensureContrast(highlight, bg, dimContrast)
+	t.CursorText = bg
+	t.SelectionBackground = color.Interpolate(bg, highlight, .3, color.CS_OKLAB, color.HUE_SHORTER).GamutMap()
+	t.SelectionForeground = ensureContrast(fg, t.SelectionBackground, minContrast)
+
+	return t
+}
@@ -0,0 +1,124 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Helix theme (TOML), to be saved as themes/<name>.toml in the config
+// directory. The scopes refer to the base16 colors in the palette table.
+
+// Helix scope with its text and background colors and modifiers
+type helixScope struct {
+	scope     string
+	fg, bg    int
+	modifiers string
+}
+
+var helixScopes = []helixScope{
+	// syntax
+	{"attribute", BASE09, BASE_NONE, ""},
+	{"comment", BASE03, BASE_NONE, "italic"},
+	{"constant", BASE09, BASE_NONE, ""},
+	{"constant.character.escape", BASE0C, BASE_NONE, ""},
+	{"constant.numeric", BASE09, BASE_NONE, ""},
+	{"constructor", BASE0D, BASE_NONE, ""},
+	{"function", BASE0D, BASE_NONE, ""},
+	{"keyword", BASE0E, BASE_NONE, ""},
+	{"label", BASE0E, BASE_NONE, ""},
+	{"namespace", BASE0E, BASE_NONE, ""},
+	{"operator", BASE05, BASE_NONE, ""},
+	{"punctuation", BASE05, BASE_NONE, ""},
+	{"special", BASE0D, BASE_NONE, ""},
+	{"string", BASE0B, BASE_NONE, ""},
+	{"string.regexp", BASE0C, BASE_NONE, ""},
+	{"tag", BASE08, BASE_NONE, ""},
+	{"type", BASE0A, BASE_NONE, ""},
+	{"variable", BASE08, BASE_NONE, ""},
+	{"variable.other.member", BASE08, BASE_NONE, ""},
+	{"variable.parameter", BASE05, BASE_NONE, ""},
+	// markup
+	{"markup.bold", BASE0A, BASE_NONE, "bold"},
+	{"markup.heading", BASE0D, BASE_NONE, "bold"},
+	{"markup.italic", BASE0E, BASE_NONE, "italic"},
+	{"markup.link.text", BASE08, BASE_NONE, ""},
+	{"markup.link.url", BASE09, BASE_NONE, "underlined"},
+	{"markup.list", BASE08, BASE_NONE, ""},
+	{"markup.quote", BASE0C, BASE_NONE, ""},
+	{"markup.raw", BASE0B, BASE_NONE, ""},
+	{"diff.delta", BASE09, BASE_NONE, ""},
+	{"diff.minus", BASE08, BASE_NONE, ""},
+	{"diff.plus", BASE0B, BASE_NONE, ""},
+	// interface
+	{"ui.background", BASE_NONE, BASE00, ""},
+	{"ui.text", BASE05, BASE_NONE, ""},
+	{"ui.text.focus", BASE05, BASE_NONE, ""},
+	{"ui.cursor", BASE00, BASE04, ""},
+	{"ui.cursor.primary", BASE00, BASE05, ""},
+	{"ui.cursor.match", BASE_NONE, BASE03, ""},
+	{"ui.cursorline.primary", BASE_NONE, BASE01, ""},
+	{"ui.selection", BASE_NONE, BASE02, ""},
+	{"ui.linenr", BASE03, BASE00, ""},
+	{"ui.linenr.selected", BASE04, BASE01, "bold"},
+	{"ui.gutter", BASE_NONE, BASE00, ""},
+	{"ui.statusline", BASE04, BASE01, ""},
+	{"ui.statusline.inactive", BASE03, BASE01, ""},
+	{"ui.statusline.normal", BASE00, BASE0D, ""},
+	{"ui.statusline.insert", BASE00, BASE0B, ""},
+	{"ui.statusline.select", BASE00, BASE0E, ""},
+	{"ui.popup", BASE05, BASE01, ""},
+	{"ui.window", BASE02, BASE00, ""},
+	{"ui.help", BASE05, BASE01, ""},
+	{"ui.menu", BASE05, BASE01, ""},
+	{"ui.menu.selected", BASE01, BASE04, ""},
+	{"ui.virtual.whitespace", BASE02, BASE_NONE, ""},
+	{"ui.virtual.ruler", BASE_NONE, BASE01, ""},
+	{"ui.virtual.inlay-hint", BASE03, BASE_NONE, ""},
+	{"error", BASE08, BASE_NONE, ""},
+	{"warning", BASE0A, BASE_NONE, ""},
+	{"info", BASE0D, BASE_NONE, ""},
+	{"hint", BASE0C, BASE_NONE, ""},
+}
+
+// Underline colors of the diagnostics
+var helixDiagnostics = []struct {
+	scope string
+	color int
+}{
+	{"diagnostic.error", BASE08},
+	{"diagnostic.warning", BASE0A},
+	{"diagnostic.info", BASE0D},
+	{"diagnostic.hint", BASE0C},
+}
+
+func WriteHelix(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+	base := t.EditorColors()
+
+	fmt.Fprintf(bw, "# %s, generated by repacolor\n\n", schemeName(t))
+	for _, s := range helixScopes {
+		var attrs []string
+		if s.fg != BASE_NONE {
+			attrs = append(attrs, fmt.Sprintf("fg = \"%s\"", Base16Names[s.fg]))
+		}
+		if s.bg != BASE_NONE {
+			attrs = append(attrs, fmt.Sprintf("bg = \"%s\"", Base16Names[s.bg]))
+		}
+		if s.modifiers != "" {
+			attrs = append(attrs, fmt.Sprintf("modifiers = [\"%s\"]", s.modifiers))
+		}
+		fmt.Fprintf(bw, "%q = { %s }\n", s.scope, strings.Join(attrs, ", "))
+	}
+	for _, d := range helixDiagnostics {
+		fmt.Fprintf(bw, "%q = { underline = { color = \"%s\", style = \"curl\" } }\n", d.scope, Base16Names[d.color])
+	}
+
+	fmt.Fprintln(bw, "\n[palette]")
+	for i, c := range base {
+		fmt.Fprintf(bw, "%s = \"%s\"\n", Base16Names[i], hex(c))
+	}
+
+	return bw.Flush()
+}
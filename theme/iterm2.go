@@ -0,0 +1,133 @@
+package theme
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// iTerm2 color preset (.itermcolors), a property list of the colors with
+// their sRGB components (0-1)
+
+const iTerm2Header = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`
+
+// Keys of the theme colors, besides "Ansi <n> Color"
+func iTerm2Colors(t Theme) []NamedColor {
+	return []NamedColor{
+		{"Background Color", t.Background},
+		{"Foreground Color", t.Foreground},
+		{"Bold Color", t.Foreground},
+		{"Cursor Color", t.Cursor},
+		{"Cursor Text Color", t.CursorText},
+		{"Selection Color", t.SelectionBackground},
+		{"Selected Text Color", t.SelectionForeground},
+	}
+}
+
+func WriteITerm2(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+
+	entry := func(key string, c color.RepaColor) {
+		r, g, b := c.RGB256()
+		fmt.Fprintf(bw, "\t<key>%s</key>\n\t<dict>\n", key)
+		fmt.Fprintf(bw, "\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n")
+		fmt.Fprintf(bw, "\t\t<key>Blue Component</key>\n\t\t<real>%.6f</real>\n", float64(b)/255)
+		fmt.Fprintf(bw, "\t\t<key>Color Space</key>\n\t\t<string>sRGB</string>\n")
+		fmt.Fprintf(bw, "\t\t<key>Green Component</key>\n\t\t<real>%.6f</real>\n", float64(g)/255)
+		fmt.Fprintf(bw, "\t\t<key>Red Component</key>\n\t\t<real>%.6f</real>\n", float64(r)/255)
+		fmt.Fprintf(bw, "\t</dict>\n")
+	}
+
+	bw.WriteString(iTerm2Header)
+	for i, c := range t.ANSI {
+		entry(fmt.Sprintf("Ansi %d Color", i), c)
+	}
+	for _, kv := range iTerm2Colors(t) {
+		entry(kv.Name, kv.Color)
+	}
+	bw.WriteString("</dict>\n</plist>\n")
+
+	return bw.Flush()
+}
+
+// Element of a property list, dicts have their keys and values as
+// alternating items
+type plistValue struct {
+	XMLName xml.Name
+	Text    string       `xml:",chardata"`
+	Items   []plistValue `xml:",any"`
+}
+
+// Key-value pairs of a plist dict
+func (v plistValue) dict() map[string]plistValue {
+	d := map[string]plistValue{}
+	for i := 0; i+1 < len(v.Items); i += 2 {
+		if v.Items[i].XMLName.Local == "key" {
+			d[v.Items[i].Text] = v.Items[i+1]
+		}
+	}
+	return d
+}
+
+// iTerm2 color preset, the components are in sRGB, Display P3 or (for older
+// presets) generic RGB, which is read as sRGB
+func ReadITerm2(data []byte) (Theme, error) {
+	var t Theme
+	var plist plistValue
+	if err := xml.Unmarshal(data, &plist); err != nil {
+		return t, err
+	}
+	if len(plist.Items) == 0 || plist.Items[0].XMLName.Local != "dict" {
+		return t, errors.New("not an iTerm2 color preset")
+	}
+
+	names := map[string]string{
+		"Background Color":    "background",
+		"Foreground Color":    "foreground",
+		"Cursor Color":        "cursor",
+		"Cursor Text Color":   "cursor-text",
+		"Selection Color":     "selection-background",
+		"Selected Text Color": "selection-foreground",
+	}
+	for i := range t.ANSI {
+		names[fmt.Sprintf("Ansi %d Color", i)] = ANSIName(i)
+	}
+
+	root := plist.Items[0]
+	for i := 0; i+1 < len(root.Items); i += 2 {
+		key, value := root.Items[i].Text, root.Items[i+1]
+		name := names[key]
+		if name == "" {
+			// bold, link, badge, ... colors
+			continue
+		}
+
+		components := value.dict()
+		var rgb [3]float64
+		for j, component := range []string{"Red Component", "Green Component", "Blue Component"} {
+			v, err := strconv.ParseFloat(strings.TrimSpace(components[component].Text), 64)
+			if err != nil {
+				return t, fmt.Errorf("%s: invalid %s", key, strings.ToLower(component))
+			}
+			rgb[j] = v
+		}
+
+		c := color.CreateColor(color.CS_RGB, rgb[0], rgb[1], rgb[2], 1)
+		if components["Color Space"].Text == "P3" {
+			c = color.CreateColor(color.CS_DISPLAYP3, rgb[0], rgb[1], rgb[2], 1)
+		}
+		t.setColor(name, c)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,87 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// kitty (kitty.conf syntax), to be included from kitty.conf:
+//
+//	include themes/<name>.conf
+func WriteKitty(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+
+	if t.Name != "" {
+		// theme metadata of kitty +kitten themes
+		fmt.Fprintf(bw, "## name: %s\n\n", t.Name)
+	}
+	for _, kv := range [][2]string{
+		{"background", hex(t.Background)},
+		{"foreground", hex(t.Foreground)},
+		{"cursor", hex(t.Cursor)},
+		{"cursor_text_color", hex(t.CursorText)},
+		{"selection_background", hex(t.SelectionBackground)},
+		{"selection_foreground", hex(t.SelectionForeground)},
+	} {
+		fmt.Fprintf(bw, "%-21s %s\n", kv[0], kv[1])
+	}
+	fmt.Fprintln(bw)
+	for i, c := range t.ANSI {
+		fmt.Fprintf(bw, "%-21s %s\n", fmt.Sprintf("color%d", i), hex(c))
+	}
+
+	return bw.Flush()
+}
+
+// kitty theme, "## name:" metadata comments are used for the name
+func ReadKitty(data []byte) (Theme, error) {
+	var t Theme
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if name, ok := strings.CutPrefix(line, "## name:"); ok {
+			t.Name = strings.TrimSpace(name)
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || isColorKeyword(fields[1]) {
+			continue
+		}
+		name := ""
+		switch key := fields[0]; key {
+		case "background", "foreground", "cursor":
+			name = key
+		case "cursor_text_color":
+			name = "cursor-text"
+		case "selection_background":
+			name = "selection-background"
+		case "selection_foreground":
+			name = "selection-foreground"
+		default:
+			n, ok := strings.CutPrefix(key, "color")
+			i, err := strconv.Atoi(n)
+			if !ok || err != nil || i < 0 || i > 15 {
+				// other settings (tab bar, borders, ...)
+				continue
+			}
+			name = ANSIName(i)
+		}
+
+		c, err := parseColor(fields[1])
+		if err != nil {
+			return t, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		t.setColor(name, c)
+	}
+
+	return t, scanner.Err()
+}
@@ -0,0 +1,61 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Neovim colorscheme (Lua), to be saved as colors/<name>.lua in the config
+// directory and loaded with :colorscheme <name>
+func WriteNeovim(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+	base := t.EditorColors()
+
+	name := schemeName(t)
+	fmt.Fprintf(bw, "-- %s, generated by repacolor\n\n", name)
+	fmt.Fprintln(bw, `vim.cmd("highlight clear")`)
+	fmt.Fprintln(bw, `if vim.fn.exists("syntax_on") == 1 then`)
+	fmt.Fprintln(bw, `  vim.cmd("syntax reset")`)
+	fmt.Fprintln(bw, "end")
+	fmt.Fprintf(bw, "vim.o.background = %q\n", background(t))
+	fmt.Fprintf(bw, "vim.g.colors_name = %q\n\n", name)
+
+	fmt.Fprintln(bw, "local c = {")
+	for i, c := range base {
+		fmt.Fprintf(bw, "  %s = \"%s\",\n", Base16Names[i], hex(c))
+	}
+	fmt.Fprintln(bw, "}")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "local hl = function(group, opts)")
+	fmt.Fprintln(bw, "  vim.api.nvim_set_hl(0, group, opts)")
+	fmt.Fprintln(bw, "end")
+	fmt.Fprintln(bw)
+
+	for _, g := range append(vimHighlightGroups, neovimHighlightGroups...) {
+		var opts []string
+		if g.fg != BASE_NONE {
+			opts = append(opts, "fg = c."+Base16Names[g.fg])
+		}
+		if g.bg != BASE_NONE {
+			opts = append(opts, "bg = c."+Base16Names[g.bg])
+		}
+		if sp, ok := undercurlColors[g.name]; ok {
+			opts = append(opts, "sp = c."+Base16Names[sp])
+		}
+		if g.style != "" {
+			for _, attr := range strings.Split(g.style, ",") {
+				opts = append(opts, attr+" = true")
+			}
+		}
+		fmt.Fprintf(bw, "hl(%q, { %s })\n", g.name, strings.Join(opts, ", "))
+	}
+
+	fmt.Fprintln(bw)
+	for i, c := range t.ANSI {
+		fmt.Fprintf(bw, "vim.g.terminal_color_%d = \"%s\"\n", i, hex(c))
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,393 @@
+// Package theme generates terminal color themes, reads and writes them in
+// the formats of terminal emulators.
+package theme
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+// Terminal color theme
+type Theme struct {
+	Name                string
+	Background          color.RepaColor
+	Foreground          color.RepaColor
+	Cursor              color.RepaColor
+	CursorText          color.RepaColor
+	SelectionBackground color.RepaColor
+	SelectionForeground color.RepaColor
+	// the 8 normal colors (see ANSINames), then their bright variants
+	ANSI [16]color.RepaColor
+	// colors of the base16 scheme the theme was read from, see EditorColors
+	Base16 [16]color.RepaColor
+}
+
+type NamedColor struct {
+	Name  string
+	Color color.RepaColor
+}
+
+var ANSINames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+const (
+	FORMAT_ALACRITTY       = iota
+	FORMAT_KITTY           = iota
+	FORMAT_FOOT            = iota
+	FORMAT_WEZTERM         = iota
+	FORMAT_WINDOWSTERMINAL = iota
+	FORMAT_ITERM2          = iota
+	FORMAT_XRESOURCES      = iota
+	FORMAT_BASE16          = iota
+	FORMAT_NEOVIM          = iota
+	FORMAT_VIM             = iota
+	FORMAT_VSCODE          = iota
+	FORMAT_HELIX           = iota
+)
+
+var FormatNames = []string{"alacritty", "kitty", "foot", "wezterm", "windows-terminal", "iterm2", "xresources", "base16", "neovim", "vim", "vscode", "helix"}
+
+// Formats that can be written, the others are read only
+var FormatWritable = []bool{true, true, true, true, true, true, true, false, true, true, true, true}
+
+// Formats that can be read, the editor formats are write only
+var FormatReadable = []bool{true, true, true, true, true, true, true, true, false, false, false, false}
+
+// TOML files can be Alacritty or WezTerm themes, see DetectContentFormat
+var formatExtensions = map[string]int{
+	".conf":        FORMAT_KITTY,
+	".ini":         FORMAT_FOOT,
+	".json":        FORMAT_WINDOWSTERMINAL,
+	".itermcolors": FORMAT_ITERM2,
+	".xresources":  FORMAT_XRESOURCES,
+	".xdefaults":   FORMAT_XRESOURCES,
+	".yaml":        FORMAT_BASE16,
+	".yml":         FORMAT_BASE16,
+}
+
+func ParseFormat(name string) (int, bool) {
+	name = strings.ToLower(name)
+	switch name {
+	case "wt", "windowsterminal":
+		return FORMAT_WINDOWSTERMINAL, true
+	case "iterm", "itermcolors":
+		return FORMAT_ITERM2, true
+	case "xrdb":
+		return FORMAT_XRESOURCES, true
+	case "base24", "tinted":
+		return FORMAT_BASE16, true
+	case "nvim", "lua":
+		return FORMAT_NEOVIM, true
+	case "code", "vs-code":
+		return FORMAT_VSCODE, true
+	case "hx":
+		return FORMAT_HELIX, true
+	}
+	for i, n := range FormatNames {
+		if n == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Format of a theme file, based on its extension
+func DetectFormat(path string) (int, bool) {
+	format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]
+	return format, ok
+}
+
+var kittyColorLine = regexp.MustCompile(`(?m)^\s*color0\s`)
+
+// Format of theme data, based on its content
+func DetectContentFormat(data []byte) (int, bool) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.Contains(data, []byte("<plist")):
+		return FORMAT_ITERM2, true
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FORMAT_WINDOWSTERMINAL, true
+	case bytes.Contains(data, []byte("base00")):
+		return FORMAT_BASE16, true
+	case bytes.Contains(data, []byte("[colors.")) || bytes.Contains(data, []byte("primary:")):
+		return FORMAT_ALACRITTY, true
+	case bytes.Contains(data, []byte("brights")):
+		return FORMAT_WEZTERM, true
+	case bytes.Contains(data, []byte("regular0")):
+		return FORMAT_FOOT, true
+	case kittyColorLine.Match(data):
+		return FORMAT_KITTY, true
+	case bytes.Contains(data, []byte("color0:")):
+		return FORMAT_XRESOURCES, true
+	}
+	return 0, false
+}
+
+// Name of the ANSI color with the given index (0-15), e.g. "bright-red"
+func ANSIName(i int) string {
+	if i >= 8 {
+		return "bright-" + ANSINames[i-8]
+	}
+	return ANSINames[i]
+}
+
+// All colors of the theme with their names: the special colors, then the
+// ANSI colors (see ANSIName)
+func (t Theme) Colors() []NamedColor {
+	colors := []NamedColor{
+		{"background", t.Background},
+		{"foreground", t.Foreground},
+		{"cursor", t.Cursor},
+		{"cursor-text", t.CursorText},
+		{"selection-background", t.SelectionBackground},
+		{"selection-foreground", t.SelectionForeground},
+	}
+	for i, c := range t.ANSI {
+		colors = append(colors, NamedColor{ANSIName(i), c})
+	}
+	return colors
+}
+
+// Dark themes have a dark background
+func (t Theme) Dark() bool {
+	l, _, _ := t.Background.Coordinates(color.CS_OKLCH)
+	return l < .6
+}
+
+// Whether the ANSI color is usually used as a background rather than for
+// text: black in dark themes, white and bright white in light themes
+func (t Theme) IsBackgroundColor(i int) bool {
+	if t.Dark() {
+		return i == 0
+	}
+	return i == 7 || i == 15
+}
+
+// Hex form of a theme color, terminals don't use the alpha channel
+func hex(c color.RepaColor) string {
+	c.A = 1
+	return c.Hex()
+}
+
+// Write the theme in the given format
+func Write(w io.Writer, t Theme, format int) error {
+	switch format {
+	case FORMAT_ALACRITTY:
+		return WriteAlacritty(w, t)
+	case FORMAT_KITTY:
+		return WriteKitty(w, t)
+	case FORMAT_FOOT:
+		return WriteFoot(w, t)
+	case FORMAT_WEZTERM:
+		return WriteWezTerm(w, t)
+	case FORMAT_WINDOWSTERMINAL:
+		return WriteWindowsTerminal(w, t)
+	case FORMAT_ITERM2:
+		return WriteITerm2(w, t)
+	case FORMAT_XRESOURCES:
+		return WriteXresources(w, t)
+	case FORMAT_NEOVIM:
+		return WriteNeovim(w, t)
+	case FORMAT_VIM:
+		return WriteVim(w, t)
+	case FORMAT_VSCODE:
+		return WriteVSCode(w, t)
+	case FORMAT_HELIX:
+		return WriteHelix(w, t)
+	}
+	if format >= 0 && format < len(FormatNames) {
+		return fmt.Errorf("%s themes cannot be written", FormatNames[format])
+	}
+	return fmt.Errorf("unknown theme format: %d", format)
+}
+
+// Read a theme in the given format. Missing bright colors are the same as
+// the normal ones, missing cursor colors are the foreground and the
+// background, the selection is shown in reverse video if it's not given.
+func Read(r io.Reader, format int) (Theme, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var t Theme
+	switch format {
+	case FORMAT_ALACRITTY:
+		t, err = ReadAlacritty(data)
+	case FORMAT_KITTY:
+		t, err = ReadKitty(data)
+	case FORMAT_FOOT:
+		t, err = ReadFoot(data)
+	case FORMAT_WEZTERM:
+		t, err = ReadWezTerm(data)
+	case FORMAT_WINDOWSTERMINAL:
+		t, err = ReadWindowsTerminal(data)
+	case FORMAT_ITERM2:
+		t, err = ReadITerm2(data)
+	case FORMAT_XRESOURCES:
+		t, err = ReadXresources(data)
+	case FORMAT_BASE16:
+		t, err = ReadBase16(data)
+	default:
+		if format >= 0 && format < len(FormatNames) {
+			return t, fmt.Errorf("%s themes cannot be read", FormatNames[format])
+		}
+		return t, fmt.Errorf("unknown theme format: %d", format)
+	}
+	if err != nil {
+		return t, err
+	}
+
+	return t, t.complete()
+}
+
+// Read a theme file, the format is detected from the extension or the
+// content. Themes without a name are named after the file.
+func ReadFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	format, ok := DetectFormat(path)
+	if !ok {
+		format, ok = DetectContentFormat(data)
+	}
+	if !ok {
+		return Theme{}, fmt.Errorf("%s: unknown theme format", path)
+	}
+
+	return ReadFileFormat(path, data, format)
+}
+
+// Read the theme file data in the given format
+func ReadFileFormat(path string, data []byte, format int) (Theme, error) {
+	t, err := Read(bytes.NewReader(data), format)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	if t.Name == "" {
+		base := filepath.Base(path)
+		t.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return t, err
+}
+
+// Fill the optional colors of a theme that was read, the background, the
+// foreground and the normal ANSI colors are required
+func (t *Theme) complete() error {
+	var missing []string
+	if t.Background == color.NOCOLOR {
+		missing = append(missing, "background")
+	}
+	if t.Foreground == color.NOCOLOR {
+		missing = append(missing, "foreground")
+	}
+	for i := 0; i < 8; i++ {
+		if t.ANSI[i] == color.NOCOLOR {
+			missing = append(missing, ANSIName(i))
+		} else if t.ANSI[i+8] == color.NOCOLOR {
+			t.ANSI[i+8] = t.ANSI[i]
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing colors: %s", strings.Join(missing, ", "))
+	}
+
+	if t.Cursor == color.NOCOLOR {
+		t.Cursor = t.Foreground
+	}
+	if t.CursorText == color.NOCOLOR {
+		t.CursorText = t.Background
+	}
+	if t.SelectionBackground == color.NOCOLOR {
+		t.SelectionBackground = t.Foreground
+	}
+	if t.SelectionForeground == color.NOCOLOR {
+		t.SelectionForeground = t.Background
+	}
+	return nil
+}
+
+// Color value of a theme file: a CSS color, a hex value without the # (foot,
+// base16), 0xRRGGBB (Alacritty) or rgb:RR/GG/BB (X11)
+func parseColor(s string) (color.RepaColor, error) {
+	s = strings.TrimSpace(s)
+	if h, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		s = "#" + h
+	} else if x11, ok := strings.CutPrefix(strings.ToLower(s), "rgb:"); ok {
+		return parseX11Color(x11)
+	} else if hexColor.MatchString(s) {
+		s = "#" + s
+	}
+
+	c, err := color.ParseColor(s, false)
+	if err != nil {
+		return c, fmt.Errorf("invalid color: %s", s)
+	}
+	c.A = 1
+	return c, nil
+}
+
+var hexColor = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+// X11 color (rgb:R/G/B), the components have 1-4 hex digits
+func parseX11Color(s string) (color.RepaColor, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return color.NOCOLOR, fmt.Errorf("invalid color: rgb:%s", s)
+	}
+	var rgb [3]float64
+	for i, part := range parts {
+		var v uint64
+		if _, err := fmt.Sscanf(part, "%x", &v); err != nil || len(part) < 1 || len(part) > 4 {
+			return color.NOCOLOR, fmt.Errorf("invalid color: rgb:%s", s)
+		}
+		rgb[i] = float64(v) / float64(uint64(1)<<(4*len(part))-1)
+	}
+	return color.CreateColor(color.CS_RGB, rgb[0], rgb[1], rgb[2], 1), nil
+}
+
+// Values of theme files that refer to other colors (kitty, Alacritty),
+// these are skipped
+func isColorKeyword(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none", "background", "foreground", "cellforeground", "cellbackground":
+		return true
+	}
+	return false
+}
+
+// Set a theme color by its name in Theme.Colors (e.g. "selection-background",
+// "bright-red"), returns false for unknown names
+func (t *Theme) setColor(name string, c color.RepaColor) bool {
+	switch name {
+	case "background":
+		t.Background = c
+	case "foreground":
+		t.Foreground = c
+	case "cursor":
+		t.Cursor = c
+	case "cursor-text":
+		t.CursorText = c
+	case "selection-background":
+		t.SelectionBackground = c
+	case "selection-foreground":
+		t.SelectionForeground = c
+	default:
+		for i := range t.ANSI {
+			if ANSIName(i) == name {
+				t.ANSI[i] = c
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
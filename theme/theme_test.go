@@ -0,0 +1,366 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dyuri/repacolor/color"
+)
+
+func parse(s string) color.RepaColor {
+	c, err := color.ParseColor(s, false)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestGenerateContrast(t *testing.T) {
+	for bg, target := range map[string]float64{"#282a36": 7, "#fdf6e3": 7, "#777777": 4.5} {
+		th := Generate(Options{Background: parse(bg), Foreground: color.NOCOLOR, Accents: []color.RepaColor{parse("#ffb86c")}, MinContrast: target})
+
+		if r := th.Foreground.ContrastRatio(th.Background); r < target {
+			t.Fatalf("%s: foreground contrast too low: %f", bg, r)
+		}
+		dark := bg != "#fdf6e3"
+		for i, c := range th.ANSI {
+			if i == 8 || (dark && i == 0) || (!dark && (i == 7 || i == 15)) {
+				continue
+			}
+			if r := c.ContrastRatio(th.Background); r < target {
+				t.Fatalf("%s: %s contrast too low: %f", bg, ANSIName(i), r)
+			}
+		}
+		if r := th.ANSI[8].ContrastRatio(th.Background); r < 3 {
+			t.Fatalf("%s: bright-black contrast too low: %f", bg, r)
+		}
+	}
+}
+
+func TestGenerateAccents(t *testing.T) {
+	blue := parse("#5588ff")
+	th := Generate(Options{Background: parse("#101010"), Foreground: color.NOCOLOR, Accents: []color.RepaColor{blue}})
+
+	if th.ANSI[4] != blue {
+		t.Fatalf("Accent should replace blue: %s", th.ANSI[4].Hex())
+	}
+	if th.Cursor != blue {
+		t.Fatalf("Accent should be the cursor color: %s", th.Cursor.Hex())
+	}
+	if th.CursorText != th.Background {
+		t.Fatalf("Cursor text should be the background: %s", th.CursorText.Hex())
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	th := Generate(Options{Background: color.NOCOLOR, Foreground: color.NOCOLOR})
+
+	if l, _, _ := th.Background.Coordinates(color.CS_OKLCH); l > .5 {
+		t.Fatalf("Default background should be dark: %s", th.Background.Hex())
+	}
+	for i, c := range th.ANSI {
+		if c == color.NOCOLOR || c.A != 1 || !c.InGamut() {
+			t.Fatalf("Invalid %s: %s", ANSIName(i), c.Hex())
+		}
+	}
+}
+
+func testTheme() Theme {
+	th := Theme{
+		Name:                "Test",
+		Background:          parse("#000000"),
+		Foreground:          parse("#ffffff"),
+		Cursor:              parse("#ff0000"),
+		CursorText:          parse("#000000"),
+		SelectionBackground: parse("#333333"),
+		SelectionForeground: parse("#eeeeee"),
+	}
+	for i := range th.ANSI {
+		th.ANSI[i] = color.CreateColor(color.CS_RGB, float64(i)/15, 0, 0, 1)
+	}
+	return th
+}
+
+func TestWriters(t *testing.T) {
+	for _, tc := range []struct {
+		format   int
+		expected []string
+	}{
+		{FORMAT_ALACRITTY, []string{"[colors.primary]\nbackground = \"#000000\"\nforeground = \"#ffffff\"\n", "[colors.bright]\nblack = \"#880000\"\n"}},
+		{FORMAT_KITTY, []string{"color15               #ff0000\n", "cursor_text_color     #000000\n"}},
+		{FORMAT_FOOT, []string{"[cursor]\ncolor=000000 ff0000\n", "bright7=ff0000\n"}},
+		{FORMAT_WEZTERM, []string{"cursor_bg = \"#ff0000\"\n", "brights = [\"#880000\", ", "name = \"Test\"\n"}},
+		{FORMAT_ITERM2, []string{"<key>Ansi 15 Color</key>\n\t<dict>\n\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n\t\t<key>Blue Component</key>\n\t\t<real>0.000000</real>\n", "<key>Red Component</key>\n\t\t<real>1.000000</real>"}},
+		{FORMAT_XRESOURCES, []string{"*.background:         #000000\n", "*.color15:            #ff0000\n"}},
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, testTheme(), tc.format); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range tc.expected {
+			if !strings.Contains(buf.String(), e) {
+				t.Fatalf("%s output should contain %q:\n%s", FormatNames[tc.format], e, buf.String())
+			}
+		}
+	}
+}
+
+func TestWriteWindowsTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testTheme(), FORMAT_WINDOWSTERMINAL); err != nil {
+		t.Fatal(err)
+	}
+
+	var scheme map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &scheme); err != nil {
+		t.Fatal(err)
+	}
+	if scheme["name"] != "Test" || scheme["purple"] != "#550000" || scheme["brightWhite"] != "#ff0000" {
+		t.Fatalf("Wrong scheme: %v", scheme)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	expected := testTheme()
+	for format, name := range FormatNames {
+		if !FormatWritable[format] || !FormatReadable[format] {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := Write(&buf, expected, format); err != nil {
+			t.Fatal(err)
+		}
+		if detected, ok := DetectContentFormat(buf.Bytes()); !ok || detected != format {
+			t.Fatalf("%s: wrong format detected: %d", name, detected)
+		}
+		th, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if th.Background.Hex() != "#000000" || th.Foreground.Hex() != "#ffffff" || th.Cursor.Hex() != "#ff0000" {
+			t.Fatalf("%s: wrong colors: %s %s %s", name, th.Background.Hex(), th.Foreground.Hex(), th.Cursor.Hex())
+		}
+		for i, c := range th.ANSI {
+			if c.Hex() != expected.ANSI[i].Hex() {
+				t.Fatalf("%s: wrong %s: %s", name, ANSIName(i), c.Hex())
+			}
+		}
+	}
+}
+
+func TestReadBase16(t *testing.T) {
+	data := `scheme: "Test"
+base00: "101010"
+base01: "202020"
+base02: "303030"
+base03: "404040"
+base04: "505050"
+base05: "d0d0d0"
+base06: "e0e0e0"
+base07: "f0f0f0"
+base08: "ff0000"
+base09: "ff8000"
+base0A: "ffff00"
+base0B: "00ff00"
+base0C: "00ffff"
+base0D: "0000ff"
+base0E: "ff00ff"
+base0F: "800000"
+`
+	th, err := Read(strings.NewReader(data), FORMAT_BASE16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Name != "Test" || th.Background.Hex() != "#101010" || th.Foreground.Hex() != "#d0d0d0" || th.SelectionBackground.Hex() != "#303030" {
+		t.Fatalf("Wrong theme: %+v", th)
+	}
+	for i, expected := range []string{"#101010", "#ff0000", "#00ff00", "#ffff00", "#0000ff", "#ff00ff", "#00ffff", "#d0d0d0", "#404040", "#ff0000"} {
+		if th.ANSI[i].Hex() != expected {
+			t.Fatalf("Wrong %s: %s", ANSIName(i), th.ANSI[i].Hex())
+		}
+	}
+
+	flat := data
+
+	// tinted-theming base24 with a palette
+	data = "system: base24\nname: Palette\npalette:\n" + strings.ReplaceAll(strings.SplitN(data, "\n", 2)[1], "base", "  base") + "  base12: \"#ff4040\"\n"
+	if th, err = Read(strings.NewReader(data), FORMAT_BASE16); err != nil {
+		t.Fatal(err)
+	}
+	if th.Name != "Palette" || th.ANSI[9].Hex() != "#ff4040" || th.ANSI[10].Hex() != "#00ff00" {
+		t.Fatalf("Wrong base24 theme: %s %s %s", th.Name, th.ANSI[9].Hex(), th.ANSI[10].Hex())
+	}
+
+	// unquoted values, YAML would read them as numbers
+	data = strings.NewReplacer("\"", "", "101010", "000000", "d0d0d0", "999999").Replace(flat)
+	if th, err = Read(strings.NewReader(data), FORMAT_BASE16); err != nil {
+		t.Fatal(err)
+	}
+	if th.Background.Hex() != "#000000" || th.Foreground.Hex() != "#999999" || th.EditorColors()[BASE0F].Hex() != "#800000" {
+		t.Fatalf("Wrong unquoted colors: %s %s", th.Background.Hex(), th.Foreground.Hex())
+	}
+}
+
+func TestReadXresources(t *testing.T) {
+	data := `! comment
+#define bg #101010
+*background: bg
+URxvt*foreground: rgb:ff/ff/ff
+*.color0: #000000
+*.color1: #cd0000
+*.color2: #00cd00
+*.color3: #cdcd00
+*.color4: #0000ee
+*.color5: #cd00cd
+*.color6: #00cdcd
+*.color7: #e5e5e5
+*.color9: #ff0000
+URxvt.scrollBar: false
+`
+	th, err := Read(strings.NewReader(data), FORMAT_XRESOURCES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Background.Hex() != "#101010" || th.Foreground.Hex() != "#ffffff" || th.ANSI[9].Hex() != "#ff0000" {
+		t.Fatalf("Wrong theme: %s %s %s", th.Background.Hex(), th.Foreground.Hex(), th.ANSI[9].Hex())
+	}
+	// missing bright colors are the normal ones, the selection is reversed
+	if th.ANSI[10].Hex() != "#00cd00" || th.SelectionBackground != th.Foreground || th.Cursor != th.Foreground {
+		t.Fatalf("Wrong defaults: %s %s %s", th.ANSI[10].Hex(), th.SelectionBackground.Hex(), th.Cursor.Hex())
+	}
+
+	if _, err := Read(strings.NewReader("*background: #000000\n"), FORMAT_XRESOURCES); err == nil || !strings.Contains(err.Error(), "foreground, black") {
+		t.Fatalf("Missing colors should be reported: %v", err)
+	}
+}
+
+func TestReadAlacrittyYAML(t *testing.T) {
+	data := `colors:
+  primary:
+    background: '0x1d1f21'
+    foreground: '0xc5c8c6'
+  cursor:
+    text: CellBackground
+    cursor: CellForeground
+  normal:
+    black: '0x1d1f21'
+    red: '0xcc6666'
+    green: '0xb5bd68'
+    yellow: '0xf0c674'
+    blue: '0x81a2be'
+    magenta: '0xb294bb'
+    cyan: '0x8abeb7'
+    white: '0xc5c8c6'
+`
+	if f, ok := DetectContentFormat([]byte(data)); !ok || f != FORMAT_ALACRITTY {
+		t.Fatalf("Wrong format detected: %d", f)
+	}
+	th, err := Read(strings.NewReader(data), FORMAT_ALACRITTY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Background.Hex() != "#1d1f21" || th.ANSI[1].Hex() != "#cc6666" || th.Cursor != th.Foreground {
+		t.Fatalf("Wrong theme: %s %s %s", th.Background.Hex(), th.ANSI[1].Hex(), th.Cursor.Hex())
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	data := `# comment
+title = "a # b" # comment
+description = """
+multi
+line"""
+[colors]
+ansi = [
+  "#000000", # black
+  '#ff0000',
+]
+search.matches = { foreground = "#000000", background = "#ffff00" }
+size = 12
+
+[[hints.enabled]]
+regex = "[a-z]+"
+`
+	root, err := parseTOML([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := lookup(root, "title"); v != "a # b" {
+		t.Fatalf("Wrong string: %v", v)
+	}
+	if v, _ := lookup(root, "description"); v != "multi\nline" {
+		t.Fatalf("Wrong multi-line string: %q", v)
+	}
+	if v, _ := lookup(root, "colors", "ansi"); len(v.([]any)) != 2 || v.([]any)[1] != "#ff0000" {
+		t.Fatalf("Wrong array: %v", v)
+	}
+	if v, _ := lookup(root, "colors", "search", "matches", "background"); v != "#ffff00" {
+		t.Fatalf("Wrong inline table: %v", v)
+	}
+	if v, _ := lookup(root, "colors", "size"); v != int64(12) {
+		t.Fatalf("Wrong integer: %v", v)
+	}
+	if _, ok := lookup(root, "hints", "enabled", "regex"); ok {
+		t.Fatalf("Arrays of tables are not tables")
+	}
+
+	for _, invalid := range []string{
+		"background = \"#000\" junk",
+		"[colors]\nbackground = \"#000\"\nbackground = \"#fff\"",
+		"[colors\nbackground = \"#000\"",
+		"background = \"#000",
+	} {
+		if _, err := parseTOML([]byte(invalid)); err == nil {
+			t.Errorf("Invalid TOML accepted: %q", invalid)
+		}
+	}
+	if _, err := ReadAlacritty([]byte("[colors.primary]\nbackground = \"#000000\" junk\n")); err == nil {
+		t.Errorf("Trailing input accepted in an Alacritty theme")
+	}
+}
+
+func TestEditorColors(t *testing.T) {
+	th := testTheme()
+	base := th.EditorColors()
+	if base[BASE00] != th.Background || base[BASE05] != th.Foreground || base[BASE03] != th.ANSI[8] || base[BASE0D] != th.ANSI[4] {
+		t.Fatalf("Wrong derived colors: %v", base)
+	}
+	if l6, _, _ := base[BASE06].Coordinates(color.CS_OKLCH); l6 < .99 {
+		t.Fatalf("base06 should be at least as light as the foreground: %s", base[BASE06].Hex())
+	}
+
+	th.Base16[BASE00] = parse("#123456")
+	if th.EditorColors() != th.Base16 {
+		t.Fatalf("The base16 colors of the scheme should be used")
+	}
+}
+
+func TestEditorWriters(t *testing.T) {
+	for _, tc := range []struct {
+		format   int
+		expected []string
+	}{
+		{FORMAT_NEOVIM, []string{"vim.g.colors_name = \"test\"\n", "  base05 = \"#ffffff\",\n", "hl(\"Comment\", { fg = c.base03, italic = true })\n", "vim.g.terminal_color_15 = \"#ff0000\"\n"}},
+		{FORMAT_VIM, []string{"set background=dark\n", "hi Normal guifg=#ffffff guibg=#000000 ctermfg=231 ctermbg=16 gui=NONE cterm=NONE\n", "hi SpellBad guifg=NONE guibg=NONE ctermfg=NONE ctermbg=NONE gui=undercurl cterm=undercurl guisp=#110000\n"}},
+		{FORMAT_VSCODE, []string{"\"type\": \"dark\"", "\"editor.background\": \"#000000\"", "\"terminal.ansiBrightWhite\": \"#ff0000\"", "\"fontStyle\": \"italic\""}},
+		{FORMAT_HELIX, []string{"\"comment\" = { fg = \"base03\", modifiers = [\"italic\"] }\n", "\"ui.background\" = { bg = \"base00\" }\n", "[palette]\nbase00 = \"#000000\"\n"}},
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, testTheme(), tc.format); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range tc.expected {
+			if !strings.Contains(buf.String(), e) {
+				t.Fatalf("%s output should contain %q:\n%s", FormatNames[tc.format], e, buf.String())
+			}
+		}
+	}
+
+	if _, err := Read(strings.NewReader(""), FORMAT_VIM); err == nil {
+		t.Fatalf("Editor formats should not be read")
+	}
+}
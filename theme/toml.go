@@ -0,0 +1,30 @@
+package theme
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+// TOML documents are read into nested maps: tables are map[string]any,
+// arrays []any, the values string, int64, float64, bool or time.Time.
+func parseTOML(data []byte) (map[string]any, error) {
+	root := map[string]any{}
+	if _, err := toml.Decode(string(data), &root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Value of the nested tables with the given path
+func lookup(root map[string]any, path ...string) (any, bool) {
+	var v any = root
+	for _, name := range path {
+		table, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = table[name]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
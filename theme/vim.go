@@ -0,0 +1,53 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Vim colorscheme, to be saved as colors/<name>.vim in ~/.vim. The cterm
+// colors are the closest colors of the 256 color palette, for terminals
+// without true color support.
+func WriteVim(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+	base := t.EditorColors()
+
+	name := schemeName(t)
+	fmt.Fprintf(bw, "\" %s, generated by repacolor\n\n", name)
+	fmt.Fprintf(bw, "set background=%s\n", background(t))
+	fmt.Fprintln(bw, "hi clear")
+	fmt.Fprintln(bw, "if exists(\"syntax_on\")")
+	fmt.Fprintln(bw, "  syntax reset")
+	fmt.Fprintln(bw, "endif")
+	fmt.Fprintf(bw, "let g:colors_name = \"%s\"\n\n", name)
+
+	attr := func(index int) (string, string) {
+		if index == BASE_NONE {
+			return "NONE", "NONE"
+		}
+		return hex(base[index]), fmt.Sprint(base[index].ANSI256())
+	}
+	for _, g := range vimHighlightGroups {
+		guifg, ctermfg := attr(g.fg)
+		guibg, ctermbg := attr(g.bg)
+		style := g.style
+		if style == "" {
+			style = "NONE"
+		}
+		fmt.Fprintf(bw, "hi %s guifg=%s guibg=%s ctermfg=%s ctermbg=%s gui=%s cterm=%s", g.name, guifg, guibg, ctermfg, ctermbg, style, style)
+		if sp, ok := undercurlColors[g.name]; ok {
+			fmt.Fprintf(bw, " guisp=%s", hex(base[sp]))
+		}
+		fmt.Fprintln(bw)
+	}
+
+	colors := make([]string, len(t.ANSI))
+	for i, c := range t.ANSI {
+		colors[i] = fmt.Sprintf("'%s'", hex(c))
+	}
+	fmt.Fprintf(bw, "\nlet g:terminal_ansi_colors = [%s]\n", strings.Join(colors, ", "))
+
+	return bw.Flush()
+}
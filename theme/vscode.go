@@ -0,0 +1,140 @@
+package theme
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// VS Code color theme (color-theme.json), to be contributed by an extension
+// in its package.json ("contributes": {"themes": [...]})
+
+type vscodeTokenSettings struct {
+	Foreground string `json:"foreground,omitempty"`
+	FontStyle  string `json:"fontStyle,omitempty"`
+}
+
+type vscodeTokenColor struct {
+	Name     string              `json:"name"`
+	Scope    []string            `json:"scope"`
+	Settings vscodeTokenSettings `json:"settings"`
+}
+
+type vscodeTheme struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Colors      map[string]string  `json:"colors"`
+	TokenColors []vscodeTokenColor `json:"tokenColors"`
+}
+
+// Workbench colors
+var vscodeColors = map[string]int{
+	"editor.background":                        BASE00,
+	"editor.foreground":                        BASE05,
+	"editorCursor.foreground":                  BASE05,
+	"editor.lineHighlightBackground":           BASE01,
+	"editor.selectionBackground":               BASE02,
+	"editor.findMatchBackground":               BASE0A,
+	"editorLineNumber.foreground":              BASE03,
+	"editorLineNumber.activeForeground":        BASE04,
+	"editorWhitespace.foreground":              BASE02,
+	"editorIndentGuide.background1":            BASE02,
+	"editorBracketMatch.background":            BASE02,
+	"editorError.foreground":                   BASE08,
+	"editorWarning.foreground":                 BASE0A,
+	"editorInfo.foreground":                    BASE0D,
+	"editorGutter.addedBackground":             BASE0B,
+	"editorGutter.modifiedBackground":          BASE0D,
+	"editorGutter.deletedBackground":           BASE08,
+	"editorWidget.background":                  BASE01,
+	"focusBorder":                              BASE0D,
+	"foreground":                               BASE05,
+	"activityBar.background":                   BASE01,
+	"activityBar.foreground":                   BASE05,
+	"activityBarBadge.background":              BASE0D,
+	"activityBarBadge.foreground":              BASE00,
+	"sideBar.background":                       BASE01,
+	"sideBar.foreground":                       BASE04,
+	"sideBarSectionHeader.background":          BASE02,
+	"statusBar.background":                     BASE02,
+	"statusBar.foreground":                     BASE04,
+	"titleBar.activeBackground":                BASE01,
+	"titleBar.activeForeground":                BASE05,
+	"tab.activeBackground":                     BASE00,
+	"tab.activeForeground":                     BASE05,
+	"tab.inactiveBackground":                   BASE01,
+	"tab.inactiveForeground":                   BASE03,
+	"editorGroupHeader.tabsBackground":         BASE01,
+	"panel.background":                         BASE00,
+	"list.activeSelectionBackground":           BASE02,
+	"list.hoverBackground":                     BASE01,
+	"input.background":                         BASE01,
+	"button.background":                        BASE0D,
+	"button.foreground":                        BASE00,
+	"gitDecoration.addedResourceForeground":    BASE0B,
+	"gitDecoration.modifiedResourceForeground": BASE0D,
+	"gitDecoration.deletedResourceForeground":  BASE08,
+	"terminal.background":                      BASE00,
+	"terminal.foreground":                      BASE05,
+}
+
+// Terminal colors, in ANSI order
+var vscodeTerminalColors = []string{
+	"terminal.ansiBlack", "terminal.ansiRed", "terminal.ansiGreen", "terminal.ansiYellow",
+	"terminal.ansiBlue", "terminal.ansiMagenta", "terminal.ansiCyan", "terminal.ansiWhite",
+	"terminal.ansiBrightBlack", "terminal.ansiBrightRed", "terminal.ansiBrightGreen", "terminal.ansiBrightYellow",
+	"terminal.ansiBrightBlue", "terminal.ansiBrightMagenta", "terminal.ansiBrightCyan", "terminal.ansiBrightWhite",
+}
+
+// TextMate scopes of the syntax colors
+var vscodeTokens = []struct {
+	name  string
+	scope []string
+	base  int
+	style string
+}{
+	{"Comments", []string{"comment", "punctuation.definition.comment"}, BASE03, "italic"},
+	{"Variables", []string{"variable", "variable.other.readwrite", "entity.name.variable"}, BASE08, ""},
+	{"Properties", []string{"variable.other.property", "support.type.property-name", "entity.other.attribute-name"}, BASE08, ""},
+	{"Constants", []string{"constant", "constant.numeric", "constant.language", "constant.character", "support.constant"}, BASE09, ""},
+	{"Types", []string{"entity.name.type", "entity.name.class", "support.type", "support.class", "storage.type"}, BASE0A, ""},
+	{"Strings", []string{"string", "markup.inline.raw"}, BASE0B, ""},
+	{"Escapes and regular expressions", []string{"constant.character.escape", "string.regexp"}, BASE0C, ""},
+	{"Functions", []string{"entity.name.function", "support.function", "meta.function-call"}, BASE0D, ""},
+	{"Keywords", []string{"keyword", "storage", "storage.modifier", "keyword.control"}, BASE0E, ""},
+	{"Operators", []string{"keyword.operator", "punctuation"}, BASE05, ""},
+	{"Tags", []string{"entity.name.tag"}, BASE08, ""},
+	{"Embedded", []string{"punctuation.section.embedded", "variable.interpolation"}, BASE0F, ""},
+	{"Headings", []string{"markup.heading", "entity.name.section"}, BASE0D, "bold"},
+	{"Links", []string{"markup.underline.link"}, BASE09, "underline"},
+	{"Inserted", []string{"markup.inserted"}, BASE0B, ""},
+	{"Deleted", []string{"markup.deleted"}, BASE08, ""},
+	{"Changed", []string{"markup.changed"}, BASE0E, ""},
+	{"Invalid", []string{"invalid", "invalid.illegal"}, BASE08, "underline"},
+}
+
+func WriteVSCode(w io.Writer, t Theme) error {
+	base := t.EditorColors()
+
+	name := t.Name
+	if name == "" {
+		name = "repacolor"
+	}
+	theme := vscodeTheme{Name: name, Type: background(t), Colors: map[string]string{}}
+	for key, index := range vscodeColors {
+		theme.Colors[key] = hex(base[index])
+	}
+	for i, key := range vscodeTerminalColors {
+		theme.Colors[key] = hex(t.ANSI[i])
+	}
+	for _, token := range vscodeTokens {
+		theme.TokenColors = append(theme.TokenColors, vscodeTokenColor{
+			Name:     token.name,
+			Scope:    token.scope,
+			Settings: vscodeTokenSettings{hex(base[token.base]), token.style},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(theme)
+}
@@ -0,0 +1,96 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WezTerm color scheme (TOML), to be placed in a directory of
+// config.color_scheme_dirs and selected with config.color_scheme
+func WriteWezTerm(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+
+	list := func(from int) string {
+		var colors []string
+		for _, c := range t.ANSI[from : from+8] {
+			colors = append(colors, fmt.Sprintf("\"%s\"", hex(c)))
+		}
+		return "[" + strings.Join(colors, ", ") + "]"
+	}
+
+	fmt.Fprintln(bw, "[colors]")
+	for _, kv := range [][2]string{
+		{"background", hex(t.Background)},
+		{"foreground", hex(t.Foreground)},
+		{"cursor_bg", hex(t.Cursor)},
+		{"cursor_fg", hex(t.CursorText)},
+		{"cursor_border", hex(t.Cursor)},
+		{"selection_bg", hex(t.SelectionBackground)},
+		{"selection_fg", hex(t.SelectionForeground)},
+	} {
+		fmt.Fprintf(bw, "%s = \"%s\"\n", kv[0], kv[1])
+	}
+	fmt.Fprintf(bw, "ansi = %s\nbrights = %s\n", list(0), list(8))
+
+	if t.Name != "" {
+		fmt.Fprintf(bw, "\n[metadata]\nname = %q\n", t.Name)
+	}
+
+	return bw.Flush()
+}
+
+// WezTerm color scheme (TOML)
+func ReadWezTerm(data []byte) (Theme, error) {
+	var t Theme
+	root, err := parseTOML(data)
+	if err != nil {
+		return t, err
+	}
+
+	if name, ok := lookup(root, "metadata", "name"); ok {
+		t.Name, _ = name.(string)
+	}
+	for _, kv := range [][2]string{
+		{"background", "background"},
+		{"foreground", "foreground"},
+		{"cursor_bg", "cursor"},
+		{"cursor_fg", "cursor-text"},
+		{"selection_bg", "selection-background"},
+		{"selection_fg", "selection-foreground"},
+	} {
+		key, name := kv[0], kv[1]
+		v, ok := lookup(root, "colors", key)
+		if !ok {
+			continue
+		}
+		s, _ := v.(string)
+		c, err := parseColor(s)
+		if err != nil {
+			return t, fmt.Errorf("colors.%s: %w", key, err)
+		}
+		t.setColor(name, c)
+	}
+
+	for j, key := range []string{"ansi", "brights"} {
+		v, ok := lookup(root, "colors", key)
+		if !ok {
+			continue
+		}
+		list, _ := v.([]any)
+		if len(list) != 8 {
+			return t, fmt.Errorf("colors.%s: 8 colors expected", key)
+		}
+		for i, item := range list {
+			s, _ := item.(string)
+			c, err := parseColor(s)
+			if err != nil {
+				return t, fmt.Errorf("colors.%s: %w", key, err)
+			}
+			t.ANSI[j*8+i] = c
+		}
+	}
+
+	return t, nil
+}
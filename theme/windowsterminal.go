@@ -0,0 +1,114 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Windows Terminal color scheme (JSON), to be added to the "schemes" of
+// settings.json
+type windowsTerminalScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background"`
+	Foreground          string `json:"foreground"`
+	CursorColor         string `json:"cursorColor"`
+	SelectionBackground string `json:"selectionBackground"`
+	Black               string `json:"black"`
+	Red                 string `json:"red"`
+	Green               string `json:"green"`
+	Yellow              string `json:"yellow"`
+	Blue                string `json:"blue"`
+	Purple              string `json:"purple"`
+	Cyan                string `json:"cyan"`
+	White               string `json:"white"`
+	BrightBlack         string `json:"brightBlack"`
+	BrightRed           string `json:"brightRed"`
+	BrightGreen         string `json:"brightGreen"`
+	BrightYellow        string `json:"brightYellow"`
+	BrightBlue          string `json:"brightBlue"`
+	BrightPurple        string `json:"brightPurple"`
+	BrightCyan          string `json:"brightCyan"`
+	BrightWhite         string `json:"brightWhite"`
+}
+
+func WriteWindowsTerminal(w io.Writer, t Theme) error {
+	var a [16]string
+	for i, c := range t.ANSI {
+		a[i] = hex(c)
+	}
+
+	name := t.Name
+	if name == "" {
+		name = "repacolor"
+	}
+	scheme := windowsTerminalScheme{
+		name, hex(t.Background), hex(t.Foreground), hex(t.Cursor), hex(t.SelectionBackground),
+		a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7],
+		a[8], a[9], a[10], a[11], a[12], a[13], a[14], a[15],
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(scheme)
+}
+
+// Windows Terminal color scheme, or the first scheme of a settings.json
+func ReadWindowsTerminal(data []byte) (Theme, error) {
+	var t Theme
+	var scheme windowsTerminalScheme
+	if err := json.Unmarshal(data, &scheme); err != nil {
+		return t, err
+	}
+	if scheme.Background == "" {
+		var settings struct {
+			Schemes []windowsTerminalScheme `json:"schemes"`
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return t, err
+		}
+		if len(settings.Schemes) == 0 {
+			return t, errors.New("no color scheme found")
+		}
+		scheme = settings.Schemes[0]
+	}
+
+	t.Name = scheme.Name
+	for _, nc := range []struct {
+		name  string
+		value string
+	}{
+		{"background", scheme.Background},
+		{"foreground", scheme.Foreground},
+		{"cursor", scheme.CursorColor},
+		{"selection-background", scheme.SelectionBackground},
+		{"black", scheme.Black},
+		{"red", scheme.Red},
+		{"green", scheme.Green},
+		{"yellow", scheme.Yellow},
+		{"blue", scheme.Blue},
+		{"magenta", scheme.Purple},
+		{"cyan", scheme.Cyan},
+		{"white", scheme.White},
+		{"bright-black", scheme.BrightBlack},
+		{"bright-red", scheme.BrightRed},
+		{"bright-green", scheme.BrightGreen},
+		{"bright-yellow", scheme.BrightYellow},
+		{"bright-blue", scheme.BrightBlue},
+		{"bright-magenta", scheme.BrightPurple},
+		{"bright-cyan", scheme.BrightCyan},
+		{"bright-white", scheme.BrightWhite},
+	} {
+		if nc.value == "" {
+			continue
+		}
+		c, err := parseColor(nc.value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", nc.name, err)
+		}
+		t.setColor(nc.name, c)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Xresources (xterm, urxvt, st, ...), to be loaded with xrdb -merge
+func WriteXresources(w io.Writer, t Theme) error {
+	bw := bufio.NewWriter(w)
+
+	if t.Name != "" {
+		fmt.Fprintf(bw, "! %s\n\n", t.Name)
+	}
+	for _, nc := range []NamedColor{
+		{"background", t.Background},
+		{"foreground", t.Foreground},
+		{"cursorColor", t.Cursor},
+		{"highlightColor", t.SelectionBackground},
+		{"highlightTextColor", t.SelectionForeground},
+	} {
+		fmt.Fprintf(bw, "*.%-19s %s\n", nc.Name+":", hex(nc.Color))
+	}
+	fmt.Fprintln(bw)
+	for i, c := range t.ANSI {
+		fmt.Fprintf(bw, "*.%-19s %s\n", fmt.Sprintf("color%d:", i), hex(c))
+	}
+
+	return bw.Flush()
+}
+
+// Xresources, the colors of any class (*, URxvt, XTerm, ...) are read,
+// #define macros are substituted
+func ReadXresources(data []byte) (Theme, error) {
+	var t Theme
+	defines := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if define, ok := strings.CutPrefix(line, "#define"); ok {
+			if fields := strings.Fields(define); len(fields) == 2 {
+				defines[fields[0]] = fields[1]
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		resource, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if v, ok := defines[value]; ok {
+			value = v
+		}
+		// the last component of the resource name
+		key := resource[strings.LastIndexAny(resource, ".*")+1:]
+
+		name := ""
+		switch key {
+		case "background", "foreground":
+			name = key
+		case "cursorColor":
+			name = "cursor"
+		case "highlightColor":
+			name = "selection-background"
+		case "highlightTextColor":
+			name = "selection-foreground"
+		default:
+			n, ok := strings.CutPrefix(key, "color")
+			i, err := strconv.Atoi(n)
+			if !ok || err != nil || i < 0 || i > 15 {
+				continue
+			}
+			name = ANSIName(i)
+		}
+
+		c, err := parseColor(value)
+		if err != nil {
+			return t, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		t.setColor(name, c)
+	}
+
+	return t, scanner.Err()
+}